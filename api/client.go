@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/utils"
+	"github.com/slack-go/slack"
+)
+
+var _ core.SlackClient = (*slack.Client)(nil)
+
+func NewClient(workspace core.Workspace) *slack.Client {
+	return slack.New(workspace.Token, slack.OptionHTTPClient(NewHTTPClient(workspace)))
+}
+
+func NewHTTPClient(workspace core.Workspace) *http.Client {
+	return utils.NewCookieHTTP("https://slack.com", utils.ConvertCookies([]http.Cookie{{Name: "d", Value: workspace.Cookie}}))
+}
+
+func LoadCacheOrEmpty(workspace string) (*core.Cache, bool) {
+	cache := LoadCache(workspace)
+	if cache != nil {
+		return cache, false
+	}
+
+	return &core.Cache{
+		Conversations: make(map[string]*core.Conversation),
+		Users:         make(map[string]*core.User),
+		Workspace:     workspace,
+	}, true
+}
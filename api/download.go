@@ -0,0 +1,209 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/utils"
+	"github.com/slack-go/slack"
+)
+
+func DownloadsDir(cfg core.Config) (string, error) {
+	if cfg.Downloads != "" {
+		return utils.ExpandHome(cfg.Downloads), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Downloads"), nil
+}
+
+func DownloadFile(workspace core.Workspace, file core.File, dir string, progress func(sent, total int64)) (string, error) {
+	if file.URLPrivate == "" {
+		return "", fmt.Errorf("%v has no download link", fileName(file))
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	path, done := downloadPath(dir, file)
+	if done {
+		return path, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, file.URLPrivate, nil)
+	if err != nil {
+		return "", err
+	}
+	if u, err := url.Parse(file.URLPrivate); err == nil && isSlackHost(u.Hostname()) {
+		req.Header.Set("Authorization", "Bearer "+workspace.Token)
+	}
+
+	resp, err := NewHTTPClient(workspace).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("couldn't download %v: %v", fileName(file), resp.Status)
+	}
+	if contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); contentType == "text/html" && file.Mimetype != "text/html" {
+		return "", fmt.Errorf("got a Slack login page instead of %v, your token or cookie may have expired", fileName(file))
+	}
+
+	tmp, err := os.CreateTemp(dir, ".hackcli-download-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	var body io.Reader = resp.Body
+	if progress != nil {
+		body = &progressReader{reader: resp.Body, total: max(resp.ContentLength, int64(file.Size)), percent: -1, progress: progress}
+	}
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	markDownloaded(path, file.ID)
+	return path, nil
+}
+
+func fileName(file core.File) string {
+	name := filepath.Base(utils.Sanitize(file.Name))
+	if name == "." || name == string(filepath.Separator) || strings.TrimSpace(name) == "" {
+		return file.ID
+	}
+	return name
+}
+
+func downloadPath(dir string, file core.File) (string, bool) {
+	name := fileName(file)
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	for i := 1; ; i++ {
+		path := filepath.Join(dir, name)
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return path, false
+		}
+		if file.ID != "" && downloadedFileID(path) == file.ID && (file.Size == 0 || info.Size() == int64(file.Size)) {
+			return path, true
+		}
+		name = fmt.Sprintf("%v (%d)%v", base, i, ext)
+	}
+}
+
+func FilesFromPermalink(api core.SlackClient, permalink string) ([]core.File, error) {
+	u, err := url.Parse(permalink)
+	if err != nil {
+		return nil, err
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+	switch {
+	case len(segments) >= 3 && segments[0] == "files":
+		file, _, _, err := api.GetFileInfo(segments[2], 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		return []core.File{ConvertFile(*file)}, nil
+	case len(segments) >= 3 && segments[0] == "archives":
+		ts, ok := tsFromPermalink(segments[2])
+		if !ok {
+			break
+		}
+
+		mes, err := messageAt(api, segments[1], ts, u.Query().Get("thread_ts"))
+		if err != nil {
+			return nil, err
+		}
+		if len(mes.Files) == 0 {
+			return nil, fmt.Errorf("that message has no files")
+		}
+
+		var files []core.File
+		for _, file := range mes.Files {
+			files = append(files, ConvertFile(file))
+		}
+		return files, nil
+	}
+	return nil, fmt.Errorf("%v is not a message or file permalink", permalink)
+}
+
+func tsFromPermalink(segment string) (string, bool) {
+	digits, ok := strings.CutPrefix(segment, "p")
+	if !ok || len(digits) <= 6 {
+		return "", false
+	}
+	if _, err := strconv.ParseUint(digits, 10, 64); err != nil {
+		return "", false
+	}
+	return digits[:len(digits)-6] + "." + digits[len(digits)-6:], true
+}
+
+func messageAt(api core.SlackClient, channelID, ts, threadTs string) (slack.Message, error) {
+	if threadTs == "" || threadTs == ts {
+		res, err := api.GetConversationHistory(&slack.GetConversationHistoryParameters{
+			ChannelID: channelID,
+			Latest:    ts,
+			Oldest:    ts,
+			Inclusive: true,
+			Limit:     1,
+		})
+		if err != nil {
+			return slack.Message{}, err
+		}
+		if len(res.Messages) == 0 || res.Messages[0].Timestamp != ts {
+			return slack.Message{}, fmt.Errorf("message not found")
+		}
+		return res.Messages[0], nil
+	}
+
+	cursor := ""
+	for {
+		replies, hasMore, next, err := api.GetConversationReplies(&slack.GetConversationRepliesParameters{
+			ChannelID: channelID,
+			Timestamp: threadTs,
+			Latest:    ts,
+			Oldest:    ts,
+			Inclusive: true,
+			Cursor:    cursor,
+			Limit:     100,
+		})
+		if err != nil {
+			return slack.Message{}, err
+		}
+		for _, reply := range replies {
+			if reply.Timestamp == ts {
+				return reply, nil
+			}
+		}
+		if !hasMore || next == "" {
+			return slack.Message{}, fmt.Errorf("message not found")
+		}
+		cursor = next
+	}
+}
@@ -0,0 +1,20 @@
+//go:build linux || darwin
+
+package api
+
+import "golang.org/x/sys/unix"
+
+const fileIDAttr = "user.hackcli.file_id"
+
+func downloadedFileID(path string) string {
+	buf := make([]byte, 64)
+	n, err := unix.Getxattr(path, fileIDAttr, buf)
+	if err != nil {
+		return ""
+	}
+	return string(buf[:n])
+}
+
+func markDownloaded(path, fileID string) {
+	unix.Setxattr(path, fileIDAttr, []byte(fileID), 0)
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package api
+
+func downloadedFileID(path string) string {
+	return ""
+}
+
+func markDownloaded(path, fileID string) {}
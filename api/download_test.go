@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Jan-Kur/HackCLI/core"
+)
+
+func TestDownloadFileComparesFileIDs(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(r.URL.Path[1:]))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	first := core.File{ID: "F1", Name: "notes.txt", Size: 5, URLPrivate: server.URL + "/aaaaa"}
+	second := core.File{ID: "F2", Name: "notes.txt", Size: 5, URLPrivate: server.URL + "/bbbbb"}
+
+	path, err := DownloadFile(core.Workspace{}, first, dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(dir, "notes.txt") {
+		t.Fatalf("first download went to %v", path)
+	}
+	if downloadedFileID(path) == "" {
+		t.Skip("the temp dir doesn't support extended attributes")
+	}
+
+	path, err = DownloadFile(core.Workspace{}, second, dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(dir, "notes (1).txt") {
+		t.Fatalf("a different file with the same name and size went to %v", path)
+	}
+
+	path, err = DownloadFile(core.Workspace{}, first, dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(dir, "notes.txt") || requests.Load() != 2 {
+		t.Fatalf("downloading the first file again went to %v after %d requests", path, requests.Load())
+	}
+}
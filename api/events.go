@@ -43,9 +43,11 @@ type MessageEvent struct {
 	Text             string            `json:"text,omitempty"`
 	Timestamp        string            `json:"ts,omitempty"`
 	ThreadTimestamp  string            `json:"thread_ts,omitempty"`
+	ParentUserID     string            `json:"parent_user_id,omitempty"`
 	IsStarred        bool              `json:"is_starred,omitempty"`
 	PinnedTo         []string          `json:"pinned_to,omitempty"`
 	Attachments      []core.Attachment `json:"attachments,omitempty"`
+	Blocks           []core.Block      `json:"blocks,omitempty"`
 	Files            []core.File       `json:"files,omitempty"`
 	LastRead         string            `json:"last_read,omitempty"`
 	Subscribed       bool              `json:"subscribed,omitempty"`
@@ -57,8 +59,10 @@ type MessageEvent struct {
 	DeletedTimestamp string            `json:"deleted_ts,omitempty"`
 	EventTimestamp   string            `json:"event_ts,omitempty"`
 	Message          struct {
-		Ts   string `json:"ts"`
-		Text string `json:"text"`
+		Ts          string            `json:"ts"`
+		Text        string            `json:"text"`
+		Attachments []core.Attachment `json:"attachments,omitempty"`
+		Blocks      []core.Block      `json:"blocks,omitempty"`
 	} `json:"message,omitempty"`
 }
 
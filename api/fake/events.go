@@ -0,0 +1,66 @@
+package fake
+
+import (
+	"context"
+
+	"github.com/Jan-Kur/HackCLI/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type Events struct {
+	events chan any
+}
+
+func NewEvents() *Events {
+	return &Events{events: make(chan any, 256)}
+}
+
+func (e *Events) Run(ctx context.Context, msgChan chan tea.Msg) {
+	send := func(msg tea.Msg) bool {
+		select {
+		case msgChan <- msg:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	if !send(core.ConnectionStateMsg{State: core.Connecting}) || !send(core.ConnectionStateMsg{State: core.Connected}) {
+		return
+	}
+
+	for {
+		var event any
+		var ok bool
+		select {
+		case event, ok = <-e.events:
+			if !ok {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+
+		if event == nil {
+			if !send(core.ConnectionStateMsg{State: core.Disconnected}) || !send(core.ConnectionStateMsg{State: core.Connected, Reconnected: true}) {
+				return
+			}
+			continue
+		}
+		if !send(core.HandleEventMsg{Event: event}) {
+			return
+		}
+	}
+}
+
+func (e *Events) Push(event any) {
+	e.events <- event
+}
+
+func (e *Events) Reconnect() {
+	e.events <- nil
+}
+
+func (e *Events) Close() {
+	close(e.events)
+}
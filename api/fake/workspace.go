@@ -0,0 +1,879 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/slack-go/slack"
+)
+
+var _ core.SlackClient = (*Workspace)(nil)
+
+type Workspace struct {
+	mu       sync.Mutex
+	self     string
+	users    map[string]*slack.User
+	presence map[string]string
+	channels map[string]*channel
+	files    map[string]*slack.File
+	emoji    map[string]string
+	lastTs   int64
+	nextID   int
+	events   *Events
+}
+
+type channel struct {
+	info     slack.Channel
+	members  []string
+	messages []slack.Message
+}
+
+func NewWorkspace(selfID, selfName string) *Workspace {
+	w := &Workspace{
+		self:     selfID,
+		users:    make(map[string]*slack.User),
+		presence: make(map[string]string),
+		channels: make(map[string]*channel),
+		files:    make(map[string]*slack.File),
+		emoji:    make(map[string]string),
+		events:   NewEvents(),
+	}
+	w.AddUser(selfID, selfName)
+	return w
+}
+
+func (w *Workspace) Events() *Events {
+	return w.events
+}
+
+func (w *Workspace) AddUser(id, name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	user := &slack.User{ID: id, Name: name, RealName: name}
+	user.Profile.DisplayName = name
+	w.users[id] = user
+	w.presence[id] = "active"
+}
+
+func (w *Workspace) SetPresence(userID, presence string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.presence[userID] = presence
+}
+
+func (w *Workspace) AddEmoji(name, url string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.emoji[name] = url
+}
+
+func (w *Workspace) AddChannel(id, name string, private bool, members ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch := &channel{members: members}
+	ch.info.ID = id
+	ch.info.Name = name
+	ch.info.IsChannel = true
+	ch.info.IsPrivate = private
+	w.channels[id] = ch
+}
+
+func (w *Workspace) AddDM(id, userID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch := &channel{members: []string{w.self, userID}}
+	ch.info.ID = id
+	ch.info.IsIM = true
+	ch.info.User = userID
+	w.channels[id] = ch
+}
+
+func (w *Workspace) AddMessage(channelID, userID, text, threadTs string) (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch, err := w.channel(channelID)
+	if err != nil {
+		return "", err
+	}
+
+	mes := slack.Message{Msg: slack.Msg{
+		Timestamp:       w.nextTs(),
+		ThreadTimestamp: threadTs,
+		User:            userID,
+		Text:            text,
+	}}
+	w.insert(ch, mes)
+	return mes.Timestamp, nil
+}
+
+func (w *Workspace) Receive(channelID, userID, text, threadTs string) (string, error) {
+	ts, err := w.AddMessage(channelID, userID, text, threadTs)
+	if err != nil {
+		return "", err
+	}
+
+	w.events.Push(&api.MessageEvent{
+		Type:            "message",
+		Channel:         channelID,
+		User:            userID,
+		Text:            text,
+		Timestamp:       ts,
+		ThreadTimestamp: threadTs,
+		ParentUserID:    w.parentUser(channelID, threadTs),
+	})
+	return ts, nil
+}
+
+func (w *Workspace) AuthTest() (*slack.AuthTestResponse, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return &slack.AuthTestResponse{
+		URL:    "https://fake.slack.com/",
+		Team:   "fake",
+		TeamID: "T00000000",
+		User:   w.users[w.self].Name,
+		UserID: w.self,
+	}, nil
+}
+
+func (w *Workspace) GetConversationHistory(params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch, err := w.channel(params.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []slack.Message
+	for _, mes := range slices.Backward(ch.messages) {
+		if isTopLevel(mes) && inRange(mes.Timestamp, params.Oldest, params.Latest, params.Inclusive) {
+			matching = append(matching, mes)
+		}
+	}
+
+	page, next := paginate(matching, params.Cursor, params.Limit)
+
+	res := &slack.GetConversationHistoryResponse{Messages: page, HasMore: next != ""}
+	res.Ok = true
+	res.ResponseMetaData.NextCursor = next
+	return res, nil
+}
+
+func (w *Workspace) GetConversationReplies(params *slack.GetConversationRepliesParameters) ([]slack.Message, bool, string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch, err := w.channel(params.ChannelID)
+	if err != nil {
+		return nil, false, "", err
+	}
+
+	var matching []slack.Message
+	for _, mes := range ch.messages {
+		if mes.Timestamp == params.Timestamp || mes.ThreadTimestamp == params.Timestamp {
+			matching = append(matching, mes)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, false, "", slack.SlackErrorResponse{Err: "thread_not_found"}
+	}
+
+	page, next := paginate(matching, params.Cursor, params.Limit)
+	return page, next != "", next, nil
+}
+
+func (w *Workspace) GetConversationInfo(input *slack.GetConversationInfoInput) (*slack.Channel, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch, err := w.channel(input.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := w.info(ch)
+	return &info, nil
+}
+
+func (w *Workspace) GetConversations(params *slack.GetConversationsParameters) ([]slack.Channel, string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	page, next := paginate(w.list(params.Types, false), params.Cursor, params.Limit)
+	return page, next, nil
+}
+
+func (w *Workspace) GetConversationsForUser(params *slack.GetConversationsForUserParameters) ([]slack.Channel, string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	page, next := paginate(w.list(params.Types, true), params.Cursor, params.Limit)
+	return page, next, nil
+}
+
+func (w *Workspace) OpenConversation(params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if params.ChannelID != "" {
+		ch, err := w.channel(params.ChannelID)
+		if err != nil {
+			return nil, false, false, err
+		}
+		info := w.info(ch)
+		return &info, false, true, nil
+	}
+
+	if len(params.Users) != 1 {
+		return nil, false, false, slack.SlackErrorResponse{Err: "not_implemented"}
+	}
+	userID := params.Users[0]
+	if _, ok := w.users[userID]; !ok {
+		return nil, false, false, slack.SlackErrorResponse{Err: "user_not_found"}
+	}
+
+	for _, ch := range w.channels {
+		if ch.info.IsIM && ch.info.User == userID {
+			info := w.info(ch)
+			return &info, false, true, nil
+		}
+	}
+
+	ch := &channel{members: []string{w.self, userID}}
+	ch.info.ID = w.newID("D")
+	ch.info.IsIM = true
+	ch.info.User = userID
+	w.channels[ch.info.ID] = ch
+
+	info := w.info(ch)
+	return &info, false, false, nil
+}
+
+func (w *Workspace) JoinConversation(channelID string) (*slack.Channel, string, []string, error) {
+	w.mu.Lock()
+
+	ch, err := w.channel(channelID)
+	if err != nil {
+		w.mu.Unlock()
+		return nil, "", nil, err
+	}
+
+	alreadyIn := slices.Contains(ch.members, w.self)
+	if !alreadyIn {
+		ch.members = append(ch.members, w.self)
+	}
+	info := w.info(ch)
+	w.mu.Unlock()
+
+	if alreadyIn {
+		return &info, "already_in_channel", nil, nil
+	}
+
+	w.events.Push(&api.ChannelJoinedEvent{User: w.self, Channel: channelID, ChannelType: "C"})
+	return &info, "", nil, nil
+}
+
+func (w *Workspace) LeaveConversation(channelID string) (bool, error) {
+	w.mu.Lock()
+
+	ch, err := w.channel(channelID)
+	if err != nil {
+		w.mu.Unlock()
+		return false, err
+	}
+
+	wasIn := slices.Contains(ch.members, w.self)
+	ch.members = slices.DeleteFunc(ch.members, func(member string) bool { return member == w.self })
+	w.mu.Unlock()
+
+	if !wasIn {
+		return true, nil
+	}
+
+	w.events.Push(&api.ChannelLeftEvent{Channel: channelID})
+	return false, nil
+}
+
+func (w *Workspace) MarkConversation(channelID, ts string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch, err := w.channel(channelID)
+	if err != nil {
+		return err
+	}
+	ch.info.LastRead = ts
+	return nil
+}
+
+func (w *Workspace) PostMessage(channelID string, options ...slack.MsgOption) (string, string, error) {
+	channelID, ts, _, err := w.SendMessage(channelID, options...)
+	return channelID, ts, err
+}
+
+func (w *Workspace) SendMessage(channelID string, options ...slack.MsgOption) (string, string, string, error) {
+	_, values, err := slack.UnsafeApplyMsgOptions("", channelID, "", options...)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	w.mu.Lock()
+
+	ch, err := w.channel(channelID)
+	if err != nil {
+		w.mu.Unlock()
+		return "", "", "", err
+	}
+
+	mes := slack.Message{Msg: slack.Msg{
+		Timestamp:       w.nextTs(),
+		ThreadTimestamp: values.Get("thread_ts"),
+		User:            w.self,
+		Text:            values.Get("text"),
+	}}
+	if raw := values.Get("attachments"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mes.Attachments); err != nil {
+			w.mu.Unlock()
+			return "", "", "", err
+		}
+	}
+	if raw := values.Get("blocks"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mes.Blocks); err != nil {
+			w.mu.Unlock()
+			return "", "", "", err
+		}
+	}
+	if mes.ThreadTimestamp != "" && values.Get("reply_broadcast") == "true" {
+		mes.SubType = "thread_broadcast"
+	}
+	w.insert(ch, mes)
+	w.mu.Unlock()
+
+	parentUser := w.parentUser(channelID, mes.ThreadTimestamp)
+
+	w.events.Push(&api.MessageEvent{
+		Type:            "message",
+		Channel:         channelID,
+		User:            mes.User,
+		Text:            mes.Text,
+		Timestamp:       mes.Timestamp,
+		ThreadTimestamp: mes.ThreadTimestamp,
+		ParentUserID:    parentUser,
+		Attachments:     api.ConvertAttachments(mes.Attachments),
+		Blocks:          api.ConvertBlocks(mes.Blocks),
+		SubType:         mes.SubType,
+	})
+	return channelID, mes.Timestamp, mes.Text, nil
+}
+
+func (w *Workspace) UpdateMessage(channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+	_, values, err := slack.UnsafeApplyMsgOptions("", channelID, "", options...)
+	if err != nil {
+		return "", "", "", err
+	}
+	text := values.Get("text")
+
+	w.mu.Lock()
+
+	mes, err := w.message(channelID, timestamp)
+	if err != nil {
+		w.mu.Unlock()
+		return "", "", "", err
+	}
+	if mes.User != w.self {
+		w.mu.Unlock()
+		return "", "", "", slack.SlackErrorResponse{Err: "cant_update_message"}
+	}
+
+	mes.Text = text
+	mes.Blocks = slack.Blocks{}
+	if raw := values.Get("blocks"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mes.Blocks); err != nil {
+			w.mu.Unlock()
+			return "", "", "", err
+		}
+	}
+	mes.Edited = &slack.Edited{User: w.self, Timestamp: w.nextTs()}
+	attachments := api.ConvertAttachments(mes.Attachments)
+	blocks := api.ConvertBlocks(mes.Blocks)
+	w.mu.Unlock()
+
+	ev := &api.MessageEvent{Type: "message", Channel: channelID, SubType: "message_changed"}
+	ev.Message.Ts = timestamp
+	ev.Message.Text = text
+	ev.Message.Attachments = attachments
+	ev.Message.Blocks = blocks
+	w.events.Push(ev)
+
+	return channelID, timestamp, text, nil
+}
+
+func (w *Workspace) DeleteMessage(channelID, messageTimestamp string) (string, string, error) {
+	w.mu.Lock()
+
+	ch, err := w.channel(channelID)
+	if err != nil {
+		w.mu.Unlock()
+		return "", "", err
+	}
+
+	i := slices.IndexFunc(ch.messages, func(mes slack.Message) bool { return mes.Timestamp == messageTimestamp })
+	if i == -1 {
+		w.mu.Unlock()
+		return "", "", slack.SlackErrorResponse{Err: "message_not_found"}
+	}
+	if ch.messages[i].User != w.self {
+		w.mu.Unlock()
+		return "", "", slack.SlackErrorResponse{Err: "cant_delete_message"}
+	}
+
+	deleted := ch.messages[i]
+	ch.messages = slices.Delete(ch.messages, i, i+1)
+	if deleted.ThreadTimestamp != "" && deleted.ThreadTimestamp != deleted.Timestamp {
+		w.updateThread(ch, deleted.ThreadTimestamp)
+	}
+	w.mu.Unlock()
+
+	w.events.Push(&api.MessageEvent{
+		Type:             "message",
+		Channel:          channelID,
+		SubType:          "message_deleted",
+		DeletedTimestamp: messageTimestamp,
+	})
+	return channelID, messageTimestamp, nil
+}
+
+func (w *Workspace) GetPermalink(params *slack.PermalinkParameters) (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	mes, err := w.message(params.Channel, params.Ts)
+	if err != nil {
+		return "", err
+	}
+	return permalink(params.Channel, *mes), nil
+}
+
+func (w *Workspace) AddReaction(name string, item slack.ItemRef) error {
+	return w.React(w.self, name, item)
+}
+
+func (w *Workspace) React(userID, name string, item slack.ItemRef) error {
+	w.mu.Lock()
+
+	mes, err := w.message(item.Channel, item.Timestamp)
+	if err != nil {
+		w.mu.Unlock()
+		return err
+	}
+
+	i := slices.IndexFunc(mes.Reactions, func(reaction slack.ItemReaction) bool { return reaction.Name == name })
+	if i == -1 {
+		mes.Reactions = append(mes.Reactions, slack.ItemReaction{Name: name})
+		i = len(mes.Reactions) - 1
+	}
+	if slices.Contains(mes.Reactions[i].Users, userID) {
+		w.mu.Unlock()
+		return slack.SlackErrorResponse{Err: "already_reacted"}
+	}
+	mes.Reactions[i].Users = append(mes.Reactions[i].Users, userID)
+	mes.Reactions[i].Count++
+	itemUser := mes.User
+	eventTs := w.nextTs()
+	w.mu.Unlock()
+
+	ev := &api.ReactionAddedEvent{Type: "reaction_added", User: userID, ItemUser: itemUser, Reaction: name, EventTimestamp: eventTs}
+	ev.Item.Type = "message"
+	ev.Item.Channel = item.Channel
+	ev.Item.Timestamp = item.Timestamp
+	w.events.Push(ev)
+	return nil
+}
+
+func (w *Workspace) RemoveReaction(name string, item slack.ItemRef) error {
+	w.mu.Lock()
+
+	mes, err := w.message(item.Channel, item.Timestamp)
+	if err != nil {
+		w.mu.Unlock()
+		return err
+	}
+
+	i := slices.IndexFunc(mes.Reactions, func(reaction slack.ItemReaction) bool { return reaction.Name == name })
+	if i == -1 || !slices.Contains(mes.Reactions[i].Users, w.self) {
+		w.mu.Unlock()
+		return slack.SlackErrorResponse{Err: "no_reaction"}
+	}
+	mes.Reactions[i].Users = slices.DeleteFunc(mes.Reactions[i].Users, func(user string) bool { return user == w.self })
+	mes.Reactions[i].Count--
+	if mes.Reactions[i].Count == 0 {
+		mes.Reactions = slices.Delete(mes.Reactions, i, i+1)
+	}
+	itemUser := mes.User
+	w.mu.Unlock()
+
+	ev := &api.ReactionRemovedEvent{Type: "reaction_removed", User: w.self, ItemUser: itemUser, Reaction: name}
+	ev.Item.Type = "message"
+	ev.Item.Channel = item.Channel
+	ev.Item.Timestamp = item.Timestamp
+	w.events.Push(ev)
+	return nil
+}
+
+func (w *Workspace) SearchMessages(query string, params slack.SearchParameters) (*slack.SearchMessages, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var terms []string
+	var inChannel, fromUser string
+	onlyThreads := false
+	for _, word := range strings.Fields(query) {
+		switch {
+		case word == "is:thread":
+			onlyThreads = true
+		case strings.HasPrefix(word, "in:"):
+			inChannel = strings.Trim(strings.TrimPrefix(word, "in:"), "<#>")
+		case strings.HasPrefix(word, "from:"):
+			fromUser = strings.Trim(strings.TrimPrefix(word, "from:"), "<@>")
+		default:
+			terms = append(terms, strings.ToLower(word))
+		}
+	}
+
+	var matches []slack.SearchMessage
+	for id, ch := range w.channels {
+		if !slices.Contains(ch.members, w.self) || (inChannel != "" && inChannel != id && inChannel != ch.info.Name) {
+			continue
+		}
+
+		for _, mes := range ch.messages {
+			if fromUser != "" && fromUser != mes.User && (w.users[mes.User] == nil || fromUser != w.users[mes.User].Name) {
+				continue
+			}
+			if onlyThreads && (mes.ThreadTimestamp == "" || mes.ThreadTimestamp == mes.Timestamp) {
+				continue
+			}
+			text := strings.ToLower(mes.Text)
+			if slices.ContainsFunc(terms, func(term string) bool { return !strings.Contains(text, term) }) {
+				continue
+			}
+
+			matches = append(matches, slack.SearchMessage{
+				Type:      "message",
+				Channel:   slack.CtxChannel{ID: id, Name: ch.info.Name},
+				User:      mes.User,
+				Timestamp: mes.Timestamp,
+				Text:      mes.Text,
+				Permalink: permalink(id, mes),
+			})
+		}
+	}
+
+	slices.SortFunc(matches, func(first, second slack.SearchMessage) int {
+		if params.SortDirection == "asc" {
+			return strings.Compare(first.Timestamp, second.Timestamp)
+		}
+		return strings.Compare(second.Timestamp, first.Timestamp)
+	})
+
+	count := params.Count
+	if count <= 0 {
+		count = slack.DEFAULT_SEARCH_COUNT
+	}
+	page := max(params.Page, 1)
+	pages := (len(matches) + count - 1) / count
+
+	start := min((page-1)*count, len(matches))
+	end := min(start+count, len(matches))
+
+	res := &slack.SearchMessages{Matches: matches[start:end], Total: len(matches)}
+	res.Paging = slack.Paging{Count: count, Total: len(matches), Page: page, Pages: pages}
+	return res, nil
+}
+
+func (w *Workspace) GetUserInfo(userID string) (*slack.User, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	user, ok := w.users[userID]
+	if !ok {
+		return nil, slack.SlackErrorResponse{Err: "user_not_found"}
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (w *Workspace) GetUserPresence(userID string) (*slack.UserPresence, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	presence, ok := w.presence[userID]
+	if !ok {
+		return nil, slack.SlackErrorResponse{Err: "user_not_found"}
+	}
+	return &slack.UserPresence{Presence: presence, Online: presence == "active"}, nil
+}
+
+func (w *Workspace) GetEmoji() (map[string]string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	emoji := make(map[string]string, len(w.emoji))
+	for name, url := range w.emoji {
+		emoji[name] = url
+	}
+	return emoji, nil
+}
+
+func (w *Workspace) UploadFileV2(params slack.UploadFileV2Parameters) (*slack.FileSummary, error) {
+	size := params.FileSize
+	if params.Reader != nil {
+		n, err := io.Copy(io.Discard, params.Reader)
+		if err != nil {
+			return nil, err
+		}
+		size = int(n)
+	}
+
+	w.mu.Lock()
+
+	ch, err := w.channel(params.Channel)
+	if err != nil {
+		w.mu.Unlock()
+		return nil, err
+	}
+
+	file := &slack.File{
+		ID:       w.newID("F"),
+		Name:     params.Filename,
+		Title:    params.Title,
+		Size:     size,
+		User:     w.self,
+		Mimetype: mime.TypeByExtension(filepath.Ext(params.Filename)),
+	}
+	file.URLPrivate = "https://files.fake.slack.com/files-pri/" + file.ID + "/" + file.Name
+	file.Permalink = "https://fake.slack.com/files/" + w.self + "/" + file.ID + "/" + file.Name
+
+	mes := slack.Message{Msg: slack.Msg{
+		Timestamp:       w.nextTs(),
+		ThreadTimestamp: params.ThreadTimestamp,
+		User:            w.self,
+		Text:            params.InitialComment,
+		SubType:         "file_share",
+		Files:           []slack.File{*file},
+	}}
+	w.insert(ch, mes)
+
+	share := slack.ShareFileInfo{Ts: mes.Timestamp, ThreadTs: params.ThreadTimestamp, ChannelName: ch.info.Name}
+	if ch.info.IsPrivate || ch.info.IsIM {
+		file.Shares.Private = map[string][]slack.ShareFileInfo{params.Channel: {share}}
+	} else {
+		file.Shares.Public = map[string][]slack.ShareFileInfo{params.Channel: {share}}
+	}
+	w.files[file.ID] = file
+	w.mu.Unlock()
+
+	w.events.Push(&api.MessageEvent{
+		Type:            "message",
+		Channel:         params.Channel,
+		User:            w.self,
+		Text:            mes.Text,
+		Timestamp:       mes.Timestamp,
+		ThreadTimestamp: mes.ThreadTimestamp,
+		SubType:         mes.SubType,
+		Files:           []core.File{api.ConvertFile(*file)},
+	})
+	return &slack.FileSummary{ID: file.ID, Title: file.Title}, nil
+}
+
+func (w *Workspace) GetFileInfo(fileID string, count, page int) (*slack.File, []slack.Comment, *slack.Paging, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	file, ok := w.files[fileID]
+	if !ok {
+		return nil, nil, nil, slack.SlackErrorResponse{Err: "file_not_found"}
+	}
+	copied := *file
+	return &copied, nil, &slack.Paging{Count: count, Page: page}, nil
+}
+
+func (w *Workspace) channel(channelID string) (*channel, error) {
+	ch, ok := w.channels[channelID]
+	if !ok {
+		return nil, slack.SlackErrorResponse{Err: "channel_not_found"}
+	}
+	return ch, nil
+}
+
+func (w *Workspace) message(channelID, ts string) (*slack.Message, error) {
+	ch, err := w.channel(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(ch.messages, func(mes slack.Message) bool { return mes.Timestamp == ts })
+	if i == -1 {
+		return nil, slack.SlackErrorResponse{Err: "message_not_found"}
+	}
+	return &ch.messages[i], nil
+}
+
+func (w *Workspace) parentUser(channelID, threadTs string) string {
+	if threadTs == "" {
+		return ""
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	parent, err := w.message(channelID, threadTs)
+	if err != nil {
+		return ""
+	}
+	return parent.User
+}
+
+func (w *Workspace) info(ch *channel) slack.Channel {
+	info := ch.info
+	info.IsMember = slices.Contains(ch.members, w.self)
+	info.Members = slices.Clone(ch.members)
+	info.NumMembers = len(ch.members)
+	if len(ch.messages) > 0 {
+		latest := ch.messages[len(ch.messages)-1]
+		info.Latest = &latest
+	}
+	return info
+}
+
+func (w *Workspace) list(types []string, memberOnly bool) []slack.Channel {
+	if len(types) == 0 {
+		types = []string{"public_channel"}
+	}
+
+	var channels []slack.Channel
+	for _, ch := range w.channels {
+		var kind string
+		switch {
+		case ch.info.IsIM:
+			kind = "im"
+		case ch.info.IsPrivate:
+			kind = "private_channel"
+		default:
+			kind = "public_channel"
+		}
+
+		isMember := slices.Contains(ch.members, w.self)
+		if !slices.Contains(types, kind) || (memberOnly && !isMember) || (kind != "public_channel" && !isMember) {
+			continue
+		}
+		channels = append(channels, w.info(ch))
+	}
+
+	slices.SortFunc(channels, func(first, second slack.Channel) int {
+		return strings.Compare(first.ID, second.ID)
+	})
+	return channels
+}
+
+func (w *Workspace) insert(ch *channel, mes slack.Message) {
+	i, _ := slices.BinarySearchFunc(ch.messages, mes.Timestamp, func(existing slack.Message, ts string) int {
+		return strings.Compare(existing.Timestamp, ts)
+	})
+	ch.messages = slices.Insert(ch.messages, i, mes)
+
+	if mes.ThreadTimestamp != "" && mes.ThreadTimestamp != mes.Timestamp {
+		w.updateThread(ch, mes.ThreadTimestamp)
+	}
+}
+
+func (w *Workspace) updateThread(ch *channel, parentTs string) {
+	i := slices.IndexFunc(ch.messages, func(mes slack.Message) bool { return mes.Timestamp == parentTs })
+	if i == -1 {
+		return
+	}
+
+	parent := &ch.messages[i]
+	parent.ThreadTimestamp = parentTs
+	parent.ReplyCount = 0
+	parent.ReplyUsers = nil
+	parent.LatestReply = ""
+
+	for _, mes := range ch.messages {
+		if mes.ThreadTimestamp != parentTs || mes.Timestamp == parentTs {
+			continue
+		}
+		parent.ReplyCount++
+		parent.LatestReply = mes.Timestamp
+		if !slices.Contains(parent.ReplyUsers, mes.User) {
+			parent.ReplyUsers = append(parent.ReplyUsers, mes.User)
+		}
+	}
+}
+
+func (w *Workspace) nextTs() string {
+	now := time.Now().UnixMicro()
+	w.lastTs = max(now, w.lastTs+1)
+	return fmt.Sprintf("%d.%06d", w.lastTs/1e6, w.lastTs%1e6)
+}
+
+func (w *Workspace) newID(prefix string) string {
+	w.nextID++
+	return fmt.Sprintf("%v%08d", prefix, w.nextID)
+}
+
+func isTopLevel(mes slack.Message) bool {
+	return mes.ThreadTimestamp == "" || mes.ThreadTimestamp == mes.Timestamp || mes.SubType == "thread_broadcast"
+}
+
+func inRange(ts, oldest, latest string, inclusive bool) bool {
+	if oldest != "" && (ts < oldest || (!inclusive && ts == oldest)) {
+		return false
+	}
+	if latest != "" && (ts > latest || (!inclusive && ts == latest)) {
+		return false
+	}
+	return true
+}
+
+func paginate[T any](items []T, cursor string, limit int) ([]T, string) {
+	start, _ := strconv.Atoi(cursor)
+	start = min(max(start, 0), len(items))
+
+	if limit <= 0 {
+		limit = 100
+	}
+	end := min(start+limit, len(items))
+
+	var next string
+	if end < len(items) {
+		next = strconv.Itoa(end)
+	}
+	return items[start:end], next
+}
+
+func permalink(channelID string, mes slack.Message) string {
+	link := fmt.Sprintf("https://fake.slack.com/archives/%v/p%v", channelID, strings.ReplaceAll(mes.Timestamp, ".", ""))
+	if mes.ThreadTimestamp != "" && mes.ThreadTimestamp != mes.Timestamp {
+		link += fmt.Sprintf("?thread_ts=%v&cid=%v", mes.ThreadTimestamp, channelID)
+	}
+	return link
+}
@@ -16,7 +16,15 @@ func getConfigPath() (string, error) {
 	return filepath.Join(baseDir, "HackCLI", "config.json"), nil
 }
 
-func getCachePath() (string, error) {
+func getCachePath(workspace string) (string, error) {
+	baseDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(baseDir, "HackCLI", "workspaces", workspace, "cache.json"), nil
+}
+
+func getLegacyCachePath() (string, error) {
 	baseDir, err := os.UserConfigDir()
 	if err != nil {
 		return "", err
@@ -40,9 +48,37 @@ func LoadConfig() (core.Config, error) {
 		return core.Config{}, err
 	}
 
+	if len(cfg.Workspaces) == 0 && cfg.Token != "" {
+		cfg = migrateLegacyConfig(cfg)
+	}
+
 	return cfg, nil
 }
 
+func migrateLegacyConfig(cfg core.Config) core.Config {
+	cfg.Workspaces = []core.Workspace{{
+		Name:   "Hack Club",
+		Domain: DefaultDomain,
+		Token:  cfg.Token,
+		Cookie: cfg.Cookie,
+	}}
+	cfg.ActiveWorkspace = DefaultDomain
+	cfg.Token = ""
+	cfg.Cookie = ""
+
+	legacyPath, err := getLegacyCachePath()
+	if err == nil {
+		if newPath, err := getCachePath(DefaultDomain); err == nil {
+			if err := os.MkdirAll(filepath.Dir(newPath), 0755); err == nil {
+				os.Rename(legacyPath, newPath)
+			}
+		}
+	}
+
+	SaveConfig(cfg)
+	return cfg
+}
+
 func SaveConfig(cfg core.Config) error {
 	path, err := getConfigPath()
 	if err != nil {
@@ -61,8 +97,8 @@ func SaveConfig(cfg core.Config) error {
 	return os.WriteFile(path, data, 0600)
 }
 
-func LoadCache() *core.Cache {
-	cachePath, err := getCachePath()
+func LoadCache(workspace string) *core.Cache {
+	cachePath, err := getCachePath(workspace)
 	if err != nil {
 		return nil
 	}
@@ -76,12 +112,21 @@ func LoadCache() *core.Cache {
 	if err := json.Unmarshal(data, &cache); err != nil {
 		return nil
 	}
+	cache.Workspace = workspace
 
 	return &cache
 }
 
 func SaveCache(cache core.Cache) error {
-	path, err := getCachePath()
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteCache(cache.Workspace, data)
+}
+
+func WriteCache(workspace string, data []byte) error {
+	path, err := getCachePath(workspace)
 	if err != nil {
 		return err
 	}
@@ -90,10 +135,18 @@ func SaveCache(cache core.Cache) error {
 		return err
 	}
 
-	data, err := json.MarshalIndent(cache, "", "  ")
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".cache-*.json")
 	if err != nil {
 		return err
 	}
+	defer os.Remove(tmp.Name())
 
-	return os.WriteFile(path, data, 0600)
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/images"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const maxImageBytes = 20 << 20
+
+func getImagePath(imageURL string) (string, error) {
+	baseDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(imageURL))
+	return filepath.Join(baseDir, "HackCLI", "images", hex.EncodeToString(sum[:])), nil
+}
+
+func FetchImage(workspace core.Workspace, imageURL string) tea.Cmd {
+	return func() tea.Msg {
+		img, err := loadImage(workspace, imageURL)
+		return core.ImageLoadedMsg{URL: imageURL, Image: img, Err: err}
+	}
+}
+
+func loadImage(workspace core.Workspace, imageURL string) (image.Image, error) {
+	path, err := getImagePath(imageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := os.ReadFile(path); err == nil {
+		return images.Decode(bytes.NewReader(data))
+	}
+
+	data, err := downloadImage(workspace, imageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := images.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+		os.WriteFile(path, data, 0600)
+	}
+	return img, nil
+}
+
+func downloadImage(workspace core.Workspace, imageURL string) ([]byte, error) {
+	u, err := url.Parse(imageURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return nil, fmt.Errorf("unsupported image URL %v", imageURL)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if isSlackHost(u.Hostname()) {
+		req.Header.Set("Authorization", "Bearer "+workspace.Token)
+	}
+
+	resp, err := NewHTTPClient(workspace).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("couldn't fetch image: %v", resp.Status)
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "image/") {
+		return nil, fmt.Errorf("couldn't fetch image: got %v", contentType)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImageBytes {
+		return nil, fmt.Errorf("image is larger than %d MB", maxImageBytes>>20)
+	}
+	return data, nil
+}
+
+func isSlackHost(host string) bool {
+	return host == "slack.com" || strings.HasSuffix(host, ".slack.com")
+}
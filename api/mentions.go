@@ -0,0 +1,111 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/slack-go/slack"
+)
+
+var mentionRegex = regexp.MustCompile(`(<[^>]*>|@[^\s#<]+|#[a-z0-9_-]+)`)
+
+func MentionID(cache *core.Cache, mention string) string {
+	if strings.HasPrefix(mention, "@") {
+		username := strings.TrimPrefix(mention, "@")
+		var userIDs []string
+		for _, user := range cache.Users {
+			if user.Name == username {
+				userIDs = append(userIDs, user.ID)
+			}
+		}
+		if len(userIDs) == 1 {
+			return fmt.Sprintf("<@%v>", userIDs[0])
+		} else {
+			return mention
+		}
+	} else {
+		channelName := strings.TrimPrefix(mention, "#")
+		var channelID string
+		for _, channel := range cache.Conversations {
+			if channel.Name == channelName {
+				channelID = channel.ID
+				break
+			}
+		}
+		if channelID != "" {
+			return fmt.Sprintf("<#%v>", channelID)
+		} else {
+			return mention
+		}
+	}
+}
+
+func ResolveMentions(cache *core.Cache, content string) string {
+	return mentionRegex.ReplaceAllStringFunc(content, func(mention string) string {
+		if strings.HasPrefix(mention, "<") {
+			return mention
+		}
+
+		trimmed := strings.TrimRight(mention, ".,!?;:)}]")
+		trailing := strings.TrimPrefix(mention, trimmed)
+
+		return MentionID(cache, trimmed) + trailing
+	})
+}
+
+func ResolveConversation(api core.SlackClient, cache *core.Cache, target string) (string, error) {
+	switch {
+	case strings.HasPrefix(target, "#"):
+		if id := strings.TrimSuffix(strings.TrimPrefix(MentionID(cache, target), "<#"), ">"); id != target {
+			return id, nil
+		}
+
+		name := strings.TrimPrefix(target, "#")
+		params := &slack.GetConversationsParameters{
+			Types:           []string{"public_channel", "private_channel"},
+			ExcludeArchived: true,
+			Limit:           1000,
+		}
+		channels, err := Paginate(func(cursor string) ([]slack.Channel, string, error) {
+			params.Cursor = cursor
+			return api.GetConversations(params)
+		})
+		if err != nil {
+			return "", fmt.Errorf("couldn't list channels: %w", err)
+		}
+		for _, channel := range channels {
+			if channel.Name == name {
+				return channel.ID, nil
+			}
+		}
+		return "", fmt.Errorf("unknown channel %v", target)
+
+	case strings.HasPrefix(target, "@"), strings.HasPrefix(target, "U"), strings.HasPrefix(target, "W"):
+		userID := target
+		if strings.HasPrefix(target, "@") {
+			resolved := MentionID(cache, target)
+			if resolved == target {
+				return "", fmt.Errorf("unknown or ambiguous user %v, pass their user ID instead", target)
+			}
+			userID = strings.TrimSuffix(strings.TrimPrefix(resolved, "<@"), ">")
+		}
+
+		var channel *slack.Channel
+		var err error
+		WithRetry(func() error {
+			channel, _, _, err = api.OpenConversation(&slack.OpenConversationParameters{
+				Users:    []string{userID},
+				ReturnIM: true,
+			})
+			return err
+		})
+		if err != nil {
+			return "", fmt.Errorf("couldn't open a DM with %v: %w", target, err)
+		}
+		return channel.ID, nil
+	}
+
+	return target, nil
+}
@@ -0,0 +1,25 @@
+package api
+
+import "sync"
+
+func ForEach[T any](items []T, workers int, fn func(item T)) {
+	jobs := make(chan T)
+
+	var wg sync.WaitGroup
+	for range min(workers, len(items)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range jobs {
+				fn(item)
+			}
+		}()
+	}
+
+	for _, item := range items {
+		jobs <- item
+	}
+	close(jobs)
+
+	wg.Wait()
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/slack-go/slack"
+)
+
+type Tier int
+
+const (
+	Tier1 Tier = iota + 1
+	Tier2
+	Tier3
+	Tier4
+)
+
+const maxRateLimitRetries = 3
+
+var tierRates = map[Tier]float64{
+	Tier1: 1,
+	Tier2: 20,
+	Tier3: 50,
+	Tier4: 100,
+}
+
+var methodTiers = map[string]Tier{
+	"auth.test":             Tier4,
+	"chat.postMessage":      Tier4,
+	"conversations.history": Tier3,
+	"conversations.info":    Tier3,
+	"conversations.list":    Tier2,
+	"conversations.replies": Tier3,
+	"emoji.list":            Tier2,
+	"search.messages":       Tier2,
+	"users.conversations":   Tier2,
+	"users.getPresence":     Tier3,
+	"users.info":            Tier4,
+}
+
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time        { return time.Now() }
+func (realClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+type Limiter struct {
+	mu      sync.Mutex
+	clock   Clock
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens       float64
+	capacity     float64
+	perSecond    float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+var limiters sync.Map
+
+func LimiterFor(client core.SlackClient) *Limiter {
+	if limiter, ok := limiters.Load(client); ok {
+		return limiter.(*Limiter)
+	}
+	limiter, _ := limiters.LoadOrStore(client, NewLimiter())
+	return limiter.(*Limiter)
+}
+
+func UseLimiter(client core.SlackClient, limiter *Limiter) {
+	limiters.Store(client, limiter)
+}
+
+func NewLimiter() *Limiter {
+	return NewLimiterWithClock(realClock{})
+}
+
+func NewLimiterWithClock(clock Clock) *Limiter {
+	return &Limiter{clock: clock, buckets: make(map[string]*bucket)}
+}
+
+func (l *Limiter) Call(method string, fn func() error) error {
+	var err error
+
+	for range maxRateLimitRetries {
+		l.Wait(method)
+
+		err = fn()
+		rateLimitErr, ok := err.(*slack.RateLimitedError)
+		if !ok {
+			return err
+		}
+
+		l.Block(method, rateLimitErr.RetryAfter)
+	}
+	return err
+}
+
+func (l *Limiter) Wait(method string) {
+	for {
+		l.mu.Lock()
+		wait := l.bucket(method).reserve(l.clock.Now())
+		l.mu.Unlock()
+
+		if wait == 0 {
+			return
+		}
+		l.clock.Sleep(wait)
+	}
+}
+
+func (l *Limiter) Block(method string, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.bucket(method)
+	if until := l.clock.Now().Add(d); until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+	b.tokens = 0
+}
+
+func (l *Limiter) bucket(method string) *bucket {
+	if b, ok := l.buckets[method]; ok {
+		return b
+	}
+
+	tier, ok := methodTiers[method]
+	if !ok {
+		tier = Tier3
+	}
+	perMinute := tierRates[tier]
+
+	b := &bucket{
+		tokens:    perMinute,
+		capacity:  perMinute,
+		perSecond: perMinute / 60,
+		last:      l.clock.Now(),
+	}
+	l.buckets[method] = b
+	return b
+}
+
+func (b *bucket) reserve(now time.Time) time.Duration {
+	if now.Before(b.blockedUntil) {
+		return b.blockedUntil.Sub(now)
+	}
+
+	b.tokens = min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.perSecond)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.perSecond * float64(time.Second))
+}
@@ -1,7 +1,11 @@
 package api
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Jan-Kur/HackCLI/core"
@@ -9,6 +13,11 @@ import (
 	"github.com/slack-go/slack"
 )
 
+const (
+	historyPageSize  = 100
+	maxHistoryProbes = 40
+)
+
 func WithRetry(fn func() error) {
 	for range 2 {
 		if err := fn(); err != nil {
@@ -49,111 +58,549 @@ func Paginate[T any](fetch func(cursor string) (items []T, nextCursor string, er
 	return all, nil
 }
 
-func GetChannelHistory(api *slack.Client, channelID string) tea.Cmd {
+func GetChannelHistory(api core.SlackClient, channelID string) tea.Cmd {
 	return func() tea.Msg {
+		params := &slack.GetConversationHistoryParameters{
+			ChannelID:          channelID,
+			Limit:              100,
+			IncludeAllMetadata: false,
+		}
+
+		var history *slack.GetConversationHistoryResponse
+		err := LimiterFor(api).Call("conversations.history", func() error {
+			var err error
+			history, err = api.GetConversationHistory(params)
+			return err
+		})
+		if err != nil {
+			return nil
+		}
+
+		var latestTs string
+		if len(history.Messages) > 0 {
+			latestTs = history.Messages[0].Timestamp
+		}
+		return core.HistoryLoadedMsg{
+			ChannelID: channelID,
+			Messages:  convertHistory(history.Messages),
+			LatestTs:  latestTs,
+			HasMore:   history.HasMore,
+		}
+	}
+}
 
+func GetOlderHistory(api core.SlackClient, channelID string, latest string) tea.Cmd {
+	return func() tea.Msg {
 		params := &slack.GetConversationHistoryParameters{
 			ChannelID:          channelID,
+			Latest:             latest,
+			Inclusive:          false,
 			Limit:              100,
 			IncludeAllMetadata: false,
 		}
 
-		history, err := api.GetConversationHistory(params)
+		var history *slack.GetConversationHistoryResponse
+		var err error
+		err = LimiterFor(api).Call("conversations.history", func() error {
+			history, err = api.GetConversationHistory(params)
+			return err
+		})
+		if err != nil {
+			return core.HistoryLoadedMsg{ChannelID: channelID, Older: true, HasMore: true}
+		}
+
+		return core.HistoryLoadedMsg{
+			ChannelID: channelID,
+			Messages:  convertHistory(history.Messages),
+			Older:     true,
+			HasMore:   history.HasMore,
+		}
+	}
+}
+
+func GetNewerHistory(api core.SlackClient, channelID string, oldest string) tea.Cmd {
+	return func() tea.Msg {
+		messages, complete, err := historyAfter(api, channelID, oldest)
+		if err != nil {
+			return core.HistoryLoadedMsg{ChannelID: channelID, Newer: true, HasNewer: true}
+		}
+
+		var latestTs string
+		if complete {
+			for _, mes := range messages {
+				if mes.Timestamp > latestTs {
+					latestTs = mes.Timestamp
+				}
+			}
+		}
+
+		return core.HistoryLoadedMsg{
+			ChannelID: channelID,
+			Messages:  convertHistory(messages),
+			LatestTs:  latestTs,
+			Newer:     true,
+			HasNewer:  !complete,
+		}
+	}
+}
+
+func GetHistoryAround(api core.SlackClient, channelID string, ts string) tea.Cmd {
+	return func() tea.Msg {
+		var older *slack.GetConversationHistoryResponse
+		var err error
+
+		err = LimiterFor(api).Call("conversations.history", func() error {
+			older, err = api.GetConversationHistory(&slack.GetConversationHistoryParameters{
+				ChannelID: channelID,
+				Latest:    ts,
+				Inclusive: true,
+				Limit:     50,
+			})
+			return err
+		})
 		if err != nil {
 			return nil
 		}
 
-		var loadedMessages []core.Message
+		newer, complete, err := historyAfter(api, channelID, ts)
+		if err != nil {
+			return nil
+		}
+
+		var latestTs string
+		if complete {
+			latestTs = ts
+			for _, mes := range newer {
+				if mes.Timestamp > latestTs {
+					latestTs = mes.Timestamp
+				}
+			}
+		}
+
+		return core.HistoryLoadedMsg{
+			ChannelID: channelID,
+			Messages:  append(convertHistory(older.Messages), convertHistory(newer)...),
+			LatestTs:  latestTs,
+			HasMore:   older.HasMore,
+			HasNewer:  !complete,
+			FocusTs:   ts,
+		}
+	}
+}
+
+func historyAfter(api core.SlackClient, channelID string, oldest string) ([]slack.Message, bool, error) {
+	lower := tsMicros(oldest)
+	upper := int64(0)
+
+	var found []slack.Message
+	for range maxHistoryProbes {
+		params := &slack.GetConversationHistoryParameters{
+			ChannelID: channelID,
+			Oldest:    oldest,
+			Inclusive: false,
+			Limit:     historyPageSize,
+		}
+		if upper > 0 {
+			params.Latest = microsTs(lower + (upper-lower+1)/2)
+		}
+
+		var history *slack.GetConversationHistoryResponse
+		var err error
+		err = LimiterFor(api).Call("conversations.history", func() error {
+			history, err = api.GetConversationHistory(params)
+			return err
+		})
+		if err != nil {
+			return nil, false, err
+		}
+
+		if history.HasMore && len(history.Messages) > 0 {
+			upper = tsMicros(history.Messages[len(history.Messages)-1].Timestamp)
+		} else {
+			if upper == 0 {
+				return history.Messages, true, nil
+			}
+			found = history.Messages
+			if len(found) >= historyPageSize/2 {
+				return found, false, nil
+			}
+			lower = tsMicros(params.Latest)
+		}
+
+		if upper > 0 && upper <= lower {
+			break
+		}
+	}
+
+	if len(found) == 0 {
+		return nil, false, fmt.Errorf("couldn't find the messages after %v", oldest)
+	}
+	return found, false, nil
+}
+
+func tsMicros(ts string) int64 {
+	sec, frac, _ := strings.Cut(ts, ".")
+	seconds, _ := strconv.ParseInt(sec, 10, 64)
+	frac = (frac + "000000")[:6]
+	micros, _ := strconv.ParseInt(frac, 10, 64)
+	return seconds*1e6 + micros
+}
+
+func microsTs(micros int64) string {
+	return fmt.Sprintf("%d.%06d", micros/1e6, micros%1e6)
+}
+
+func SearchMessages(api core.SlackClient, query string, page int) tea.Cmd {
+	return func() tea.Msg {
+		params := slack.NewSearchParameters()
+		params.Sort = "timestamp"
+		params.Page = page
+
+		var res *slack.SearchMessages
+		var err error
+		err = LimiterFor(api).Call("search.messages", func() error {
+			res, err = api.SearchMessages(query, params)
+			return err
+		})
+		if err != nil {
+			return core.SearchResultsMsg{Query: query, Page: page, Err: err}
+		}
+
+		var results []core.SearchResult
+		for _, match := range res.Matches {
+			results = append(results, core.SearchResult{
+				Message: core.Message{
+					Ts:          match.Timestamp,
+					ThreadId:    threadTsFromPermalink(match.Permalink),
+					User:        match.User,
+					Content:     match.Text,
+					Attachments: ConvertAttachments(match.Attachments),
+					Blocks:      ConvertBlocks(match.Blocks),
+				},
+				ChannelID:   match.Channel.ID,
+				ChannelName: match.Channel.Name,
+				Permalink:   match.Permalink,
+			})
+		}
+
+		return core.SearchResultsMsg{
+			Query:     query,
+			Results:   results,
+			Page:      res.Paging.Page,
+			PageCount: res.Paging.Pages,
+			Total:     res.Total,
+		}
+	}
+}
+
+func ListPublicChannels(api core.SlackClient, workspace string, cursor string) tea.Cmd {
+	return func() tea.Msg {
+		params := &slack.GetConversationsParameters{
+			Types:           []string{"public_channel"},
+			ExcludeArchived: true,
+			Cursor:          cursor,
+			Limit:           200,
+		}
+
+		var channels []slack.Channel
+		var next string
+		var err error
+		err = LimiterFor(api).Call("conversations.list", func() error {
+			channels, next, err = api.GetConversations(params)
+			return err
+		})
+		if err != nil {
+			return core.ChannelsListedMsg{Workspace: workspace, Cursor: cursor, Err: err}
+		}
 
-		for i := len(history.Messages) - 1; i >= 0; i-- {
-			slackMsg := history.Messages[i]
+		var browsed []core.BrowsedChannel
+		for _, channel := range channels {
+			browsed = append(browsed, core.BrowsedChannel{
+				ID:         channel.ID,
+				Name:       channel.Name,
+				Topic:      channel.Topic.Value,
+				Purpose:    channel.Purpose.Value,
+				NumMembers: channel.NumMembers,
+				IsMember:   channel.IsMember,
+			})
+		}
+
+		return core.ChannelsListedMsg{Workspace: workspace, Cursor: cursor, Channels: browsed, Next: next}
+	}
+}
+
+func GetChannelPreview(api core.SlackClient, workspace string, channelID string) tea.Cmd {
+	return func() tea.Msg {
+		var history *slack.GetConversationHistoryResponse
+		var err error
+		err = LimiterFor(api).Call("conversations.history", func() error {
+			history, err = api.GetConversationHistory(&slack.GetConversationHistoryParameters{
+				ChannelID: channelID,
+				Limit:     20,
+			})
+			return err
+		})
+		if err != nil {
+			return core.ChannelPreviewMsg{Workspace: workspace, ChannelID: channelID, Err: err}
+		}
+
+		return core.ChannelPreviewMsg{Workspace: workspace, ChannelID: channelID, Messages: convertHistory(history.Messages)}
+	}
+}
+
+func SearchThreads(api core.SlackClient, workspace string, userID string) tea.Cmd {
+	return func() tea.Msg {
+		var threads []core.ThreadInfo
 
-			reactions := make(map[string][]string)
-			for _, reaction := range slackMsg.Reactions {
-				reactions[reaction.Name] = reaction.Users
+		for _, query := range []string{"is:thread from:<@" + userID + ">", "is:thread <@" + userID + ">"} {
+			params := slack.NewSearchParameters()
+			params.Sort = "timestamp"
+			params.Count = 50
+
+			var res *slack.SearchMessages
+			var err error
+			err = LimiterFor(api).Call("search.messages", func() error {
+				res, err = api.SearchMessages(query, params)
+				return err
+			})
+			if err != nil {
+				continue
 			}
 
-			var files []core.File
-			for _, file := range slackMsg.Files {
-				files = append(files, core.File{
-					Permalink:  file.Permalink,
-					URLPrivate: file.URLPrivate,
+			for _, match := range res.Matches {
+				threadTs := threadTsFromPermalink(match.Permalink)
+				if threadTs == "" || threadTs == match.Timestamp {
+					continue
+				}
+
+				threads = append(threads, core.ThreadInfo{
+					ChannelID:  match.Channel.ID,
+					ThreadTs:   threadTs,
+					LatestTs:   match.Timestamp,
+					LatestUser: match.User,
+					LatestText: match.Text,
 				})
 			}
+		}
+
+		return core.ThreadsFoundMsg{Workspace: workspace, Threads: threads}
+	}
+}
+
+func SearchMentions(api core.SlackClient, workspace string, userID string) tea.Cmd {
+	return func() tea.Msg {
+		params := slack.NewSearchParameters()
+		params.Sort = "timestamp"
+		params.Count = 50
+
+		var res *slack.SearchMessages
+		var err error
+		err = LimiterFor(api).Call("search.messages", func() error {
+			res, err = api.SearchMessages("<@"+userID+">", params)
+			return err
+		})
+		if err != nil {
+			return nil
+		}
+
+		var items []core.ActivityItem
+		for _, match := range res.Matches {
+			if match.User == userID {
+				continue
+			}
 
-			loadedMessages = append(loadedMessages, core.Message{
-				Ts:         slackMsg.Timestamp,
-				ThreadId:   slackMsg.ThreadTimestamp,
-				User:       slackMsg.User,
-				Content:    slackMsg.Text,
-				Files:      files,
-				Reactions:  reactions,
-				SubType:    slackMsg.SubType,
-				ReplyCount: slackMsg.ReplyCount,
-				ReplyUsers: slackMsg.ReplyUsers,
+			items = append(items, core.ActivityItem{
+				Kind:      "mention",
+				ChannelID: match.Channel.ID,
+				Ts:        match.Timestamp,
+				ThreadTs:  threadTsFromPermalink(match.Permalink),
+				User:      match.User,
+				Text:      match.Text,
+				EventTs:   match.Timestamp,
 			})
 		}
-		var latestTs string
-		if len(history.Messages) > 0 {
-			latestTs = history.Messages[0].Timestamp
+
+		return core.ActivityFoundMsg{Workspace: workspace, Items: items}
+	}
+}
+
+func GetThreadParent(api core.SlackClient, channelID string, ts string) tea.Cmd {
+	return func() tea.Msg {
+		var replies []slack.Message
+		var err error
+		err = LimiterFor(api).Call("conversations.replies", func() error {
+			replies, _, _, err = api.GetConversationReplies(&slack.GetConversationRepliesParameters{
+				ChannelID: channelID,
+				Timestamp: ts,
+				Limit:     1,
+			})
+			return err
+		})
+		if err != nil || len(replies) == 0 {
+			return nil
+		}
+
+		return core.ThreadParentLoadedMsg{
+			ChannelID: channelID,
+			ThreadTs:  ts,
+			User:      replies[0].User,
+			Text:      replies[0].Text,
 		}
-		return core.HistoryLoadedMsg{Messages: loadedMessages, LatestTs: latestTs}
 	}
 }
 
-func GetThread(api *slack.Client, channelID string, ts string) tea.Cmd {
+func threadTsFromPermalink(permalink string) string {
+	u, err := url.Parse(permalink)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get("thread_ts")
+}
+
+func convertHistory(messages []slack.Message) []core.Message {
+	var loadedMessages []core.Message
+
+	for i := len(messages) - 1; i >= 0; i-- {
+		loadedMessages = append(loadedMessages, convertMessage(messages[i]))
+	}
+	return loadedMessages
+}
+
+func convertMessage(slackMsg slack.Message) core.Message {
+	reactions := make(map[string][]string)
+	for _, reaction := range slackMsg.Reactions {
+		reactions[reaction.Name] = reaction.Users
+	}
+
+	var files []core.File
+	for _, file := range slackMsg.Files {
+		files = append(files, ConvertFile(file))
+	}
+
+	return core.Message{
+		Ts:          slackMsg.Timestamp,
+		ThreadId:    slackMsg.ThreadTimestamp,
+		User:        slackMsg.User,
+		Content:     slackMsg.Text,
+		Attachments: ConvertAttachments(slackMsg.Attachments),
+		Blocks:      ConvertBlocks(slackMsg.Blocks),
+		Files:       files,
+		Reactions:   reactions,
+		SubType:     slackMsg.SubType,
+		ReplyCount:  slackMsg.ReplyCount,
+		ReplyUsers:  slackMsg.ReplyUsers,
+	}
+}
+
+func ConvertFile(file slack.File) core.File {
+	return core.File{
+		ID:         file.ID,
+		Name:       file.Name,
+		Title:      file.Title,
+		Mimetype:   file.Mimetype,
+		Size:       file.Size,
+		URLPrivate: file.URLPrivate,
+		Permalink:  file.Permalink,
+		Thumb360:   file.Thumb360,
+		Thumb480:   file.Thumb480,
+	}
+}
+
+func ConvertBlocks(blocks slack.Blocks) []core.Block {
+	if len(blocks.BlockSet) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(blocks)
+	if err != nil {
+		return nil
+	}
+
+	var converted []core.Block
+	if err := json.Unmarshal(data, &converted); err != nil {
+		return nil
+	}
+	return converted
+}
+
+func ConvertAttachments(attachments []slack.Attachment) []core.Attachment {
+	var converted []core.Attachment
+	for _, attachment := range attachments {
+		converted = append(converted, convertAttachment(attachment))
+	}
+	return converted
+}
+
+func convertAttachment(attachment slack.Attachment) core.Attachment {
+	var fields []core.AttachmentField
+	for _, field := range attachment.Fields {
+		fields = append(fields, core.AttachmentField{Title: field.Title, Value: field.Value, Short: field.Short})
+	}
+
+	return core.Attachment{
+		Color:       attachment.Color,
+		Fallback:    attachment.Fallback,
+		Pretext:     attachment.Pretext,
+		AuthorName:  attachment.AuthorName,
+		AuthorLink:  attachment.AuthorLink,
+		Title:       attachment.Title,
+		TitleLink:   attachment.TitleLink,
+		Text:        attachment.Text,
+		Fields:      fields,
+		Footer:      attachment.Footer,
+		ServiceName: attachment.ServiceName,
+		ImageURL:    attachment.ImageURL,
+		ThumbURL:    attachment.ThumbURL,
+		FromURL:     attachment.FromURL,
+		OriginalURL: attachment.OriginalURL,
+	}
+}
+
+func GetThread(api core.SlackClient, channelID string, ts string, cursor string) tea.Cmd {
 	return func() tea.Msg {
 		params := &slack.GetConversationRepliesParameters{
 			ChannelID:          channelID,
 			Timestamp:          ts,
+			Cursor:             cursor,
+			Limit:              100,
 			IncludeAllMetadata: false,
 		}
 
-		replies, err := Paginate(func(cursor string) ([]slack.Message, string, error) {
-			params.Cursor = cursor
-			msgs, _, nextCursor, err := api.GetConversationReplies(params)
-			return msgs, nextCursor, err
+		var replies []slack.Message
+		var nextCursor string
+		var err error
+		err = LimiterFor(api).Call("conversations.replies", func() error {
+			replies, _, nextCursor, err = api.GetConversationReplies(params)
+			return err
 		})
 		if err != nil {
-			return nil
+			if cursor == "" {
+				return nil
+			}
+			return core.ThreadLoadedMsg{ChannelID: channelID, ThreadTs: ts, Cursor: cursor, More: true, HasMore: true}
 		}
 
 		var loadedMessages []core.Message
-
 		for _, slackMsg := range replies {
+			loadedMessages = append(loadedMessages, convertMessage(slackMsg))
+		}
 
-			reactions := make(map[string][]string)
-			for _, reaction := range slackMsg.Reactions {
-				reactions[reaction.Name] = reaction.Users
-			}
-
-			var files []core.File
-			for _, file := range slackMsg.Files {
-				files = append(files, core.File{
-					Permalink:  file.Permalink,
-					URLPrivate: file.URLPrivate,
-				})
-			}
-
-			loadedMessages = append(loadedMessages, core.Message{
-				Ts:        slackMsg.Timestamp,
-				ThreadId:  slackMsg.ThreadTimestamp,
-				User:      slackMsg.User,
-				Content:   slackMsg.Text,
-				Files:     files,
-				Reactions: reactions,
-				SubType:   slackMsg.SubType,
-			})
+		return core.ThreadLoadedMsg{
+			ChannelID: channelID,
+			ThreadTs:  ts,
+			Messages:  loadedMessages,
+			Cursor:    nextCursor,
+			More:      cursor != "",
+			HasMore:   nextCursor != "",
 		}
-		return core.ThreadLoadedMsg{Messages: loadedMessages}
 	}
 }
 
-func GetUserInfo(api *slack.Client, userID string) (*slack.User, error) {
+func GetUserInfo(api core.SlackClient, userID string) (*slack.User, error) {
 	var fetchedUser *slack.User
-	var err error
 
-	WithRetry(func() error {
+	err := LimiterFor(api).Call("users.info", func() error {
+		var err error
 		fetchedUser, err = api.GetUserInfo(userID)
 		return err
 	})
@@ -165,11 +612,27 @@ func GetUserInfo(api *slack.Client, userID string) (*slack.User, error) {
 	return fetchedUser, nil
 }
 
-func GetLatestMessage(api *slack.Client, channelID string) (*slack.Message, error) {
-	history, err := api.GetConversationHistory(&slack.GetConversationHistoryParameters{
-		ChannelID:          channelID,
-		Limit:              1,
-		IncludeAllMetadata: false,
+func DisplayName(user *slack.User) string {
+	if user.Profile.DisplayName != "" {
+		return user.Profile.DisplayName
+	}
+	if user.Profile.FirstName != "" {
+		return user.Profile.FirstName
+	}
+	return user.RealName
+}
+
+func GetLatestMessage(api core.SlackClient, channelID string) (*slack.Message, error) {
+	var history *slack.GetConversationHistoryResponse
+
+	err := LimiterFor(api).Call("conversations.history", func() error {
+		var err error
+		history, err = api.GetConversationHistory(&slack.GetConversationHistoryParameters{
+			ChannelID:          channelID,
+			Limit:              1,
+			IncludeAllMetadata: false,
+		})
+		return err
 	})
 	if err != nil {
 		return nil, err
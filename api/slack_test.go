@@ -0,0 +1,81 @@
+package api_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/Jan-Kur/HackCLI/api/fake"
+	"github.com/Jan-Kur/HackCLI/core"
+)
+
+func newHistoryWorkspace(t *testing.T, count int) (*fake.Workspace, []string) {
+	t.Helper()
+
+	w := fake.NewWorkspace("U1", "me")
+	w.AddChannel("C1", "general", false, "U1")
+
+	var timestamps []string
+	for i := range count {
+		ts, err := w.AddMessage("C1", "U1", fmt.Sprintf("m%d", i), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		timestamps = append(timestamps, ts)
+	}
+	return w, timestamps
+}
+
+func assertContiguous(t *testing.T, messages []core.Message, timestamps []string, from int) {
+	t.Helper()
+
+	for i, mes := range messages {
+		if want := timestamps[from+i]; mes.Ts != want {
+			t.Fatalf("message %d: got ts %v, want %v (m%d)", i, mes.Ts, want, from+i)
+		}
+	}
+}
+
+func TestGetHistoryAroundHasNoHole(t *testing.T) {
+	w, timestamps := newHistoryWorkspace(t, 400)
+
+	msg := api.GetHistoryAround(w, "C1", timestamps[100])().(core.HistoryLoadedMsg)
+	if !msg.HasNewer {
+		t.Fatal("expected more messages after the loaded ones")
+	}
+	assertContiguous(t, msg.Messages, timestamps, 51)
+
+	loaded := len(msg.Messages)
+	for msg.HasNewer {
+		last := msg.Messages[len(msg.Messages)-1].Ts
+		msg = api.GetNewerHistory(w, "C1", last)().(core.HistoryLoadedMsg)
+		if len(msg.Messages) == 0 && msg.HasNewer {
+			t.Fatal("newer history stalled")
+		}
+		if len(msg.Messages) > 100 {
+			t.Fatalf("got %d messages in one page", len(msg.Messages))
+		}
+		assertContiguous(t, msg.Messages, timestamps, 51+loaded)
+		loaded += len(msg.Messages)
+	}
+
+	if got := 51 + loaded; got != len(timestamps) {
+		t.Fatalf("loaded up to m%d, want m%d", got-1, len(timestamps)-1)
+	}
+	if msg.LatestTs != timestamps[len(timestamps)-1] {
+		t.Fatalf("latest ts %v, want %v", msg.LatestTs, timestamps[len(timestamps)-1])
+	}
+}
+
+func TestGetHistoryAroundNearTheEnd(t *testing.T) {
+	w, timestamps := newHistoryWorkspace(t, 120)
+
+	msg := api.GetHistoryAround(w, "C1", timestamps[100])().(core.HistoryLoadedMsg)
+	if msg.HasNewer {
+		t.Fatal("expected the whole rest of the channel")
+	}
+	assertContiguous(t, msg.Messages, timestamps, 51)
+	if len(msg.Messages) != 69 {
+		t.Fatalf("got %d messages, want 69", len(msg.Messages))
+	}
+}
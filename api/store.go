@@ -0,0 +1,429 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Jan-Kur/HackCLI/core"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	metaBucket         = "meta"
+	threadBucketPrefix = "threads/"
+	threadIndexKey     = "thread_index"
+)
+
+type Store struct {
+	db     *bolt.DB
+	writes chan func(tx *bolt.Tx) error
+	done   chan struct{}
+}
+
+func getStorePath(workspace string) (string, error) {
+	baseDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(baseDir, "HackCLI", "workspaces", workspace, "messages.db"), nil
+}
+
+func getLegacyStorePath() (string, error) {
+	baseDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(baseDir, "HackCLI", "messages.db"), nil
+}
+
+func OpenStore(workspace string) (*Store, error) {
+	path, err := getStorePath(workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	if workspace == DefaultDomain {
+		if legacyPath, err := getLegacyStorePath(); err == nil {
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				os.Rename(legacyPath, path)
+			}
+		}
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Update(indexThreads); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	s := &Store{
+		db:     db,
+		writes: make(chan func(tx *bolt.Tx) error, 256),
+		done:   make(chan struct{}),
+	}
+	go s.writeLoop()
+
+	return s, nil
+}
+
+func (s *Store) Close() error {
+	if s == nil {
+		return nil
+	}
+
+	close(s.writes)
+	<-s.done
+	return s.db.Close()
+}
+
+func (s *Store) writeLoop() {
+	defer close(s.done)
+
+	for write := range s.writes {
+		s.db.Update(write)
+	}
+}
+
+func (s *Store) SaveMessages(channelID string, messages ...core.Message) {
+	if s == nil || len(messages) == 0 {
+		return
+	}
+
+	encoded, ok := encodeMessages(messages)
+	if !ok {
+		return
+	}
+
+	s.writes <- func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(channelID))
+		if err != nil {
+			return err
+		}
+
+		for ts, stored := range encoded {
+			if err := putMessage(tx, bucket, channelID, ts, stored); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func (s *Store) ReconcileHistory(channelID string, messages []core.Message) {
+	if s == nil || len(messages) == 0 {
+		return
+	}
+
+	encoded, ok := encodeMessages(messages)
+	if !ok {
+		return
+	}
+
+	oldest, newest := messages[0].Ts, messages[0].Ts
+	for _, mes := range messages {
+		oldest = min(oldest, mes.Ts)
+		newest = max(newest, mes.Ts)
+	}
+
+	s.writes <- func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(channelID))
+		if err != nil {
+			return err
+		}
+
+		var stale [][]byte
+		c := bucket.Cursor()
+		for k, v := c.Seek([]byte(oldest)); k != nil && string(k) <= newest; k, v = c.Next() {
+			if _, ok := encoded[string(k)]; ok {
+				continue
+			}
+
+			var mes core.Message
+			if json.Unmarshal(v, &mes) == nil && isTopLevel(mes) {
+				stale = append(stale, slices.Clone(k))
+			}
+		}
+
+		for _, k := range stale {
+			if err := deleteMessage(tx, bucket, channelID, string(k)); err != nil {
+				return err
+			}
+		}
+
+		for ts, stored := range encoded {
+			if err := putMessage(tx, bucket, channelID, ts, stored); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func (s *Store) UpdateMessage(channelID, ts string, update func(mes *core.Message)) {
+	if s == nil {
+		return
+	}
+
+	s.writes <- func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(channelID))
+		if bucket == nil {
+			return nil
+		}
+
+		data := bucket.Get([]byte(ts))
+		if data == nil {
+			return nil
+		}
+
+		var mes core.Message
+		if err := json.Unmarshal(data, &mes); err != nil {
+			return err
+		}
+		if mes.Reactions == nil {
+			mes.Reactions = make(map[string][]string)
+		}
+
+		update(&mes)
+
+		data, err := json.Marshal(mes)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(ts), data)
+	}
+}
+
+func (s *Store) DeleteMessage(channelID, ts string) {
+	if s == nil {
+		return
+	}
+
+	s.writes <- func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(channelID))
+		if bucket == nil {
+			return nil
+		}
+		return deleteMessage(tx, bucket, channelID, ts)
+	}
+}
+
+func (s *Store) Messages(channelID, before string, limit int) []core.Message {
+	if s == nil {
+		return nil
+	}
+
+	var messages []core.Message
+
+	s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(channelID))
+		if bucket == nil {
+			return nil
+		}
+
+		c := bucket.Cursor()
+
+		var k, v []byte
+		if before == "" {
+			k, v = c.Last()
+		} else if k, _ = c.Seek([]byte(before)); k == nil {
+			k, v = c.Last()
+		} else {
+			k, v = c.Prev()
+		}
+
+		for ; k != nil && len(messages) < limit; k, v = c.Prev() {
+			var mes core.Message
+			if json.Unmarshal(v, &mes) == nil && isTopLevel(mes) {
+				messages = append(messages, mes)
+			}
+		}
+		return nil
+	})
+
+	slices.Reverse(messages)
+	return messages
+}
+
+func (s *Store) Message(channelID, ts string) (core.Message, bool) {
+	var mes core.Message
+	if s == nil {
+		return mes, false
+	}
+
+	found := false
+	s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(channelID))
+		if bucket == nil {
+			return nil
+		}
+
+		if data := bucket.Get([]byte(ts)); data != nil {
+			found = json.Unmarshal(data, &mes) == nil
+		}
+		return nil
+	})
+	return mes, found
+}
+
+func (s *Store) Replies(channelID, parentTs string) []core.Message {
+	if s == nil {
+		return nil
+	}
+
+	var messages []core.Message
+
+	s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(channelID))
+		if bucket == nil {
+			return nil
+		}
+
+		var parent core.Message
+		if data := bucket.Get([]byte(parentTs)); data == nil || json.Unmarshal(data, &parent) != nil {
+			return nil
+		}
+		messages = append(messages, parent)
+
+		threads := tx.Bucket([]byte(threadBucketPrefix + channelID))
+		if threads == nil {
+			return nil
+		}
+
+		prefix := []byte(parentTs + "/")
+		c := threads.Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			var mes core.Message
+			if data := bucket.Get(k[len(prefix):]); data != nil && json.Unmarshal(data, &mes) == nil {
+				messages = append(messages, mes)
+			}
+		}
+		return nil
+	})
+	return messages
+}
+
+type storedMessage struct {
+	data     []byte
+	threadId string
+}
+
+func putMessage(tx *bolt.Tx, bucket *bolt.Bucket, channelID, ts string, stored storedMessage) error {
+	if err := bucket.Put([]byte(ts), stored.data); err != nil {
+		return err
+	}
+	if stored.threadId == "" {
+		return nil
+	}
+
+	threads, err := tx.CreateBucketIfNotExists([]byte(threadBucketPrefix + channelID))
+	if err != nil {
+		return err
+	}
+	return threads.Put(threadKey(stored.threadId, ts), nil)
+}
+
+func deleteMessage(tx *bolt.Tx, bucket *bolt.Bucket, channelID, ts string) error {
+	var mes core.Message
+	if data := bucket.Get([]byte(ts)); data != nil && json.Unmarshal(data, &mes) == nil && isReply(mes) {
+		if threads := tx.Bucket([]byte(threadBucketPrefix + channelID)); threads != nil {
+			if err := threads.Delete(threadKey(mes.ThreadId, ts)); err != nil {
+				return err
+			}
+		}
+	}
+	return bucket.Delete([]byte(ts))
+}
+
+func threadKey(threadId, ts string) []byte {
+	return []byte(threadId + "/" + ts)
+}
+
+func indexThreads(tx *bolt.Tx) error {
+	meta, err := tx.CreateBucketIfNotExists([]byte(metaBucket))
+	if err != nil {
+		return err
+	}
+	if meta.Get([]byte(threadIndexKey)) != nil {
+		return nil
+	}
+
+	var channels []string
+	tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+		if !isChannelBucket(string(name)) {
+			return nil
+		}
+		channels = append(channels, string(name))
+		return nil
+	})
+
+	for _, channelID := range channels {
+		bucket := tx.Bucket([]byte(channelID))
+		var replies [][]byte
+
+		bucket.ForEach(func(k, v []byte) error {
+			var mes core.Message
+			if json.Unmarshal(v, &mes) == nil && isReply(mes) {
+				replies = append(replies, threadKey(mes.ThreadId, string(k)))
+			}
+			return nil
+		})
+		if len(replies) == 0 {
+			continue
+		}
+
+		threads, err := tx.CreateBucketIfNotExists([]byte(threadBucketPrefix + channelID))
+		if err != nil {
+			return err
+		}
+		for _, key := range replies {
+			if err := threads.Put(key, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	return meta.Put([]byte(threadIndexKey), []byte("1"))
+}
+
+func isChannelBucket(name string) bool {
+	return name != metaBucket && !strings.HasPrefix(name, threadBucketPrefix)
+}
+
+func encodeMessages(messages []core.Message) (map[string]storedMessage, bool) {
+	encoded := make(map[string]storedMessage, len(messages))
+
+	for _, mes := range messages {
+		data, err := json.Marshal(mes)
+		if err != nil {
+			return nil, false
+		}
+
+		stored := storedMessage{data: data}
+		if isReply(mes) {
+			stored.threadId = mes.ThreadId
+		}
+		encoded[mes.Ts] = stored
+	}
+	return encoded, true
+}
+
+func isTopLevel(mes core.Message) bool {
+	return mes.ThreadId == "" || mes.ThreadId == mes.Ts || mes.SubType == "thread_broadcast"
+}
+
+func isReply(mes core.Message) bool {
+	return mes.ThreadId != "" && mes.ThreadId != mes.Ts
+}
@@ -0,0 +1,58 @@
+package api_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/Jan-Kur/HackCLI/core"
+)
+
+func replyTimestamps(messages []core.Message) []string {
+	var timestamps []string
+	for _, mes := range messages {
+		timestamps = append(timestamps, mes.Ts)
+	}
+	return timestamps
+}
+
+func TestStoreReplies(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	store, err := api.OpenStore("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.SaveMessages("C1",
+		core.Message{Ts: "100.000001", ThreadId: "100.000001", Content: "parent"},
+		core.Message{Ts: "100.000002", Content: "unrelated"},
+		core.Message{Ts: "100.000003", ThreadId: "100.000001", Content: "first reply"},
+		core.Message{Ts: "100.000004", ThreadId: "100.000002", Content: "other thread"},
+		core.Message{Ts: "100.000005", ThreadId: "100.000001", Content: "broadcast", SubType: "thread_broadcast"},
+	)
+	store.DeleteMessage("C1", "100.000003")
+	store.SaveMessages("C1", core.Message{Ts: "100.000006", ThreadId: "100.000001", Content: "second reply"})
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = api.OpenStore("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	got := replyTimestamps(store.Replies("C1", "100.000001"))
+	if want := []string{"100.000001", "100.000005", "100.000006"}; !slices.Equal(got, want) {
+		t.Errorf("Replies = %v, want %v", got, want)
+	}
+
+	if replies := store.Replies("C1", "100.000009"); replies != nil {
+		t.Errorf("Replies of a missing parent = %v, want nil", replyTimestamps(replies))
+	}
+
+	got = replyTimestamps(store.Messages("C1", "", 10))
+	if want := []string{"100.000001", "100.000002", "100.000005"}; !slices.Equal(got, want) {
+		t.Errorf("Messages = %v, want %v", got, want)
+	}
+}
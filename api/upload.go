@@ -0,0 +1,134 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/slack-go/slack"
+)
+
+type progressReader struct {
+	reader   io.Reader
+	sent     int64
+	total    int64
+	percent  int64
+	progress func(sent, total int64)
+}
+
+func (p *progressReader) Read(buf []byte) (int, error) {
+	n, err := p.reader.Read(buf)
+	p.sent += int64(n)
+
+	if percent := p.sent * 100 / max(p.total, 1); percent != p.percent || err == io.EOF {
+		p.percent = percent
+		p.progress(p.sent, p.total)
+	}
+	return n, err
+}
+
+func UploadFile(api core.SlackClient, channelID, threadTs, path, comment string, progress func(sent, total int64)) (*slack.File, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%v is a directory", path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if progress != nil {
+		reader = &progressReader{reader: file, total: info.Size(), percent: -1, progress: progress}
+	}
+
+	summary, err := api.UploadFileV2(slack.UploadFileV2Parameters{
+		Reader:          reader,
+		FileSize:        int(info.Size()),
+		Filename:        filepath.Base(path),
+		Title:           filepath.Base(path),
+		InitialComment:  comment,
+		Channel:         channelID,
+		ThreadTimestamp: threadTs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var uploaded *slack.File
+	for range 5 {
+		WithRetry(func() error {
+			uploaded, _, _, err = api.GetFileInfo(summary.ID, 0, 0)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if FileShareTs(uploaded, channelID) != "" {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+
+	return uploaded, nil
+}
+
+func FileShareTs(file *slack.File, channelID string) string {
+	for _, shares := range []map[string][]slack.ShareFileInfo{file.Shares.Public, file.Shares.Private} {
+		if infos := shares[channelID]; len(infos) > 0 {
+			return infos[0].Ts
+		}
+	}
+	return ""
+}
+
+func FindFileMessageTs(api core.SlackClient, file *slack.File, channelID, threadTs string) (string, error) {
+	oldest := strconv.FormatInt(int64(file.Created)-60, 10)
+
+	var messages []slack.Message
+	var err error
+	if threadTs != "" {
+		err = LimiterFor(api).Call("conversations.replies", func() error {
+			messages, _, _, err = api.GetConversationReplies(&slack.GetConversationRepliesParameters{
+				ChannelID: channelID,
+				Timestamp: threadTs,
+				Oldest:    oldest,
+				Limit:     100,
+			})
+			return err
+		})
+	} else {
+		err = LimiterFor(api).Call("conversations.history", func() error {
+			var res *slack.GetConversationHistoryResponse
+			res, err = api.GetConversationHistory(&slack.GetConversationHistoryParameters{
+				ChannelID: channelID,
+				Oldest:    oldest,
+				Limit:     100,
+			})
+			if err == nil {
+				messages = res.Messages
+			}
+			return err
+		})
+	}
+	if err != nil {
+		return "", err
+	}
+
+	for _, mes := range messages {
+		if slices.ContainsFunc(mes.Files, func(f slack.File) bool { return f.ID == file.ID }) {
+			return mes.Timestamp, nil
+		}
+	}
+	return "", fmt.Errorf("no message with %v found", file.Name)
+}
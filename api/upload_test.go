@@ -0,0 +1,48 @@
+package api_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/Jan-Kur/HackCLI/api/fake"
+	"github.com/slack-go/slack"
+)
+
+func TestFindFileMessageTs(t *testing.T) {
+	w := fake.NewWorkspace("U1", "me")
+	w.AddChannel("C1", "general", false, "U1")
+	if _, err := w.AddMessage("C1", "U1", "before", ""); err != nil {
+		t.Fatal(err)
+	}
+	parent, err := w.AddMessage("C1", "U1", "parent", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, threadTs := range []string{"", parent} {
+		file, err := api.UploadFile(w, "C1", threadTs, path, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := api.FileShareTs(file, "C1")
+		if want == "" {
+			t.Fatalf("thread %q: upload wasn't shared", threadTs)
+		}
+
+		file.Shares = slack.Share{}
+		got, err := api.FindFileMessageTs(w, file, "C1", threadTs)
+		if err != nil {
+			t.Fatalf("thread %q: %v", threadTs, err)
+		}
+		if got != want {
+			t.Errorf("thread %q: got ts %q, want %q", threadTs, got, want)
+		}
+	}
+}
@@ -1,8 +1,10 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"math/rand"
 	"net/http"
 	"reflect"
 	"time"
@@ -18,32 +20,111 @@ const (
 	pongWait = 60 * time.Second
 
 	pingPeriod = (pongWait * 9) / 10
+
+	minBackoff = 1 * time.Second
+
+	maxBackoff = 60 * time.Second
+
+	stableConnection = 30 * time.Second
 )
 
-func RunWebsocket(token, cookie string, msgChan chan tea.Msg) {
+type Websocket struct {
+	Token  string
+	Cookie string
+}
+
+func (w Websocket) Run(ctx context.Context, msgChan chan tea.Msg) {
+	RunWebsocket(ctx, w.Token, w.Cookie, msgChan)
+}
+
+func RunWebsocket(ctx context.Context, token, cookie string, msgChan chan tea.Msg) {
 	headers := http.Header{}
 	headers.Add("Cookie", fmt.Sprintf("d=%v", cookie))
 	headers.Add("Origin", "https://app.slack.com")
 	headers.Add("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/139.0.0.0 Safari/537.36")
 
-	url := "wss://wss-primary.slack.com/?token=" + token
-	conn, _, err := websocket.DefaultDialer.Dial(url, headers)
-	if err != nil {
-		panic("Failed to connect to websocket")
+	SuperviseWebsocket(ctx, "wss://wss-primary.slack.com/?token="+token, headers, msgChan)
+}
+
+func SuperviseWebsocket(ctx context.Context, url string, headers http.Header, msgChan chan tea.Msg) {
+	backoff := minBackoff
+	connectedBefore := false
+
+	for {
+		if !send(ctx, msgChan, core.ConnectionStateMsg{State: core.Connecting}) {
+			return
+		}
+
+		conn, _, err := websocket.DefaultDialer.DialContext(ctx, url, headers)
+		if err == nil {
+			if !send(ctx, msgChan, core.ConnectionStateMsg{State: core.Connected, Reconnected: connectedBefore}) {
+				conn.Close()
+				return
+			}
+			connectedBefore = true
+
+			connectedAt := time.Now()
+			readEvents(ctx, conn, msgChan)
+			if time.Since(connectedAt) >= stableConnection {
+				backoff = minBackoff
+			}
+		}
+
+		retryIn := backoff + time.Duration(rand.Int63n(int64(backoff/4)+1))
+		if !send(ctx, msgChan, core.ConnectionStateMsg{State: core.Disconnected, RetryIn: retryIn}) || !wait(ctx, retryIn) {
+			return
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+func send(ctx context.Context, msgChan chan tea.Msg, msg tea.Msg) bool {
+	select {
+	case msgChan <- msg:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func wait(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
 	}
+}
+
+func readEvents(ctx context.Context, conn *websocket.Conn, msgChan chan tea.Msg) {
+	defer conn.Close()
 
 	conn.SetReadDeadline(time.Now().Add(pongWait))
 	conn.SetPongHandler(func(string) error { conn.SetReadDeadline(time.Now().Add(pongWait)); return nil })
 
-	defer conn.Close()
+	done := make(chan struct{})
+	defer close(done)
+
+	go ack(conn, done)
 
-	go ack(conn)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
 
 	for {
 		_, msg, err := conn.ReadMessage()
 		if err != nil {
-			break
+			return
 		}
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+
 		var initialEvent InitialEvent
 		if err := json.Unmarshal(msg, &initialEvent); err != nil {
 			continue
@@ -53,16 +134,25 @@ func RunWebsocket(token, cookie string, msgChan chan tea.Msg) {
 		if finalEvent == nil {
 			continue
 		}
-		msgChan <- core.HandleEventMsg{Event: finalEvent}
+		if !send(ctx, msgChan, core.HandleEventMsg{Event: finalEvent}) {
+			return
+		}
 	}
 }
 
-func ack(conn *websocket.Conn) {
+func ack(conn *websocket.Conn, done chan struct{}) {
 	ticker := time.NewTicker(pingPeriod)
-	for range ticker.C {
-		conn.SetWriteDeadline(time.Now().Add(writeWait))
-		if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
 			return
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
 		}
 	}
 }
@@ -73,28 +163,27 @@ func MessageHandler(msgChan chan tea.Msg, ev *MessageEvent) {
 		msgChan <- core.DeletedMessageMsg{DeletedTs: ev.DeletedTimestamp}
 		return
 	case "message_changed":
-		msgChan <- core.EditedMessageMsg{Ts: ev.Message.Ts, Content: ev.Message.Text}
+		msgChan <- core.EditedMessageMsg{
+			Ts:          ev.Message.Ts,
+			Content:     ev.Message.Text,
+			Attachments: ev.Message.Attachments,
+			Blocks:      ev.Message.Blocks,
+		}
 		return
 	}
 
-	var files []core.File
-	for _, file := range ev.Files {
-		files = append(files, core.File{
-			Permalink:  file.Permalink,
-			URLPrivate: file.URLPrivate,
-		})
-	}
-
 	message := core.Message{
-		Ts:         ev.Timestamp,
-		ThreadId:   ev.ThreadTimestamp,
-		User:       ev.User,
-		Content:    ev.Text,
-		Files:      files,
-		Reactions:  make(map[string][]string),
-		SubType:    ev.SubType,
-		ReplyCount: ev.ReplyCount,
-		ReplyUsers: ev.ReplyUsers,
+		Ts:          ev.Timestamp,
+		ThreadId:    ev.ThreadTimestamp,
+		User:        ev.User,
+		Content:     ev.Text,
+		Attachments: ev.Attachments,
+		Blocks:      ev.Blocks,
+		Files:       ev.Files,
+		Reactions:   make(map[string][]string),
+		SubType:     ev.SubType,
+		ReplyCount:  ev.ReplyCount,
+		ReplyUsers:  ev.ReplyUsers,
 	}
 
 	msgChan <- core.NewMessageMsg{Message: message}
@@ -0,0 +1,137 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/Jan-Kur/HackCLI/core"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gorilla/websocket"
+)
+
+func newWebsocketServer(t *testing.T) (string, *atomic.Int32) {
+	var connections atomic.Int32
+	upgrader := websocket.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		switch connections.Add(1) {
+		case 1:
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"message","channel":"C1","user":"U2","text":"hi","ts":"1.000001"}`))
+			return
+		case 2:
+			return
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http"), &connections
+}
+
+func nextMsg(t *testing.T, msgChan chan tea.Msg) tea.Msg {
+	t.Helper()
+
+	select {
+	case msg := <-msgChan:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a message")
+		return nil
+	}
+}
+
+func expectState(t *testing.T, msgChan chan tea.Msg, want core.ConnectionState, reconnected bool) core.ConnectionStateMsg {
+	t.Helper()
+
+	state, ok := nextMsg(t, msgChan).(core.ConnectionStateMsg)
+	if !ok || state.State != want || state.Reconnected != reconnected {
+		t.Fatalf("got %#v, want state %v (reconnected %v)", state, want, reconnected)
+	}
+	return state
+}
+
+func TestSuperviseWebsocketReconnects(t *testing.T) {
+	url, connections := newWebsocketServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	msgChan := make(chan tea.Msg)
+	stopped := make(chan struct{})
+	go func() {
+		api.SuperviseWebsocket(ctx, url, nil, msgChan)
+		close(stopped)
+	}()
+
+	expectState(t, msgChan, core.Connecting, false)
+	expectState(t, msgChan, core.Connected, false)
+
+	event, ok := nextMsg(t, msgChan).(core.HandleEventMsg)
+	if !ok {
+		t.Fatalf("got %#v, want the message event", event)
+	}
+	if ev, ok := event.Event.(*api.MessageEvent); !ok || ev.Text != "hi" || ev.Channel != "C1" {
+		t.Fatalf("got %#v, want the message from the server", event.Event)
+	}
+
+	if state := expectState(t, msgChan, core.Disconnected, false); state.RetryIn <= 0 {
+		t.Fatalf("disconnected without a retry delay: %#v", state)
+	}
+	expectState(t, msgChan, core.Connecting, false)
+	expectState(t, msgChan, core.Connected, true)
+
+	if state := expectState(t, msgChan, core.Disconnected, false); state.RetryIn < 2*time.Second {
+		t.Fatalf("backoff was reset after a short-lived connection: %#v", state)
+	}
+	expectState(t, msgChan, core.Connecting, false)
+	expectState(t, msgChan, core.Connected, true)
+
+	if got := connections.Load(); got != 3 {
+		t.Fatalf("server saw %d connections, want 3", got)
+	}
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("SuperviseWebsocket kept running after its context was cancelled")
+	}
+}
+
+func TestSuperviseWebsocketStopsWhileWaiting(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	msgChan := make(chan tea.Msg)
+	stopped := make(chan struct{})
+	go func() {
+		api.SuperviseWebsocket(ctx, url, nil, msgChan)
+		close(stopped)
+	}()
+
+	expectState(t, msgChan, core.Connecting, false)
+	expectState(t, msgChan, core.Disconnected, false)
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("SuperviseWebsocket kept waiting after its context was cancelled")
+	}
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Jan-Kur/HackCLI/core"
+)
+
+const DefaultDomain = "hackclub"
+
+func NormalizeDomain(domain string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	domain = strings.TrimPrefix(domain, "https://")
+	domain = strings.TrimPrefix(domain, "http://")
+	domain = strings.TrimSuffix(domain, "/")
+	return strings.TrimSuffix(domain, ".slack.com")
+}
+
+func FindWorkspace(cfg core.Config, name string) (core.Workspace, error) {
+	if len(cfg.Workspaces) == 0 {
+		return core.Workspace{}, fmt.Errorf("no workspaces configured, run `hackcli init` first")
+	}
+
+	explicit := name != ""
+	if !explicit {
+		name = cfg.ActiveWorkspace
+	}
+
+	i := slices.IndexFunc(cfg.Workspaces, func(ws core.Workspace) bool {
+		return ws.Domain == NormalizeDomain(name) || strings.EqualFold(ws.Name, name)
+	})
+	if i == -1 && !explicit {
+		return cfg.Workspaces[0], nil
+	}
+	if i == -1 {
+		return core.Workspace{}, fmt.Errorf("unknown workspace %q, add it with `hackcli init --workspace %v`", name, NormalizeDomain(name))
+	}
+	return cfg.Workspaces[i], nil
+}
+
+func PutWorkspace(cfg *core.Config, workspace core.Workspace) {
+	i := slices.IndexFunc(cfg.Workspaces, func(ws core.Workspace) bool { return ws.Domain == workspace.Domain })
+	if i == -1 {
+		cfg.Workspaces = append(cfg.Workspaces, workspace)
+	} else {
+		cfg.Workspaces[i] = workspace
+	}
+
+	if cfg.ActiveWorkspace == "" {
+		cfg.ActiveWorkspace = workspace.Domain
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/utils"
+	"github.com/spf13/cobra"
+)
+
+var DownloadCmd = &cobra.Command{
+	Use:   "download <permalink>",
+	Short: "Downloads the files of a message",
+	Long: `Downloads a file, or every file of a message, from its permalink and prints where it was saved.
+	Files go to the downloads directory from the config (~/Downloads by default) unless --dir is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDownload,
+}
+
+func init() {
+	DownloadCmd.Flags().String("dir", "", "directory to save the files in")
+	DownloadCmd.Flags().Bool("open", false, "open the files with the system opener after downloading")
+
+	RootCmd.AddCommand(DownloadCmd)
+}
+
+func runDownload(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	dir, _ := cmd.Flags().GetString("dir")
+	open, _ := cmd.Flags().GetBool("open")
+
+	cfg, err := api.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("couldn't load config, run `hackcli init` first: %w", err)
+	}
+	workspaceName, _ := cmd.Flags().GetString("workspace")
+	if workspaceName == "" {
+		workspaceName = permalinkWorkspace(cfg, args[0])
+	}
+	workspace, err := api.FindWorkspace(cfg, workspaceName)
+	if err != nil {
+		return err
+	}
+
+	if dir == "" {
+		if dir, err = api.DownloadsDir(cfg); err != nil {
+			return err
+		}
+	}
+	dir = utils.ExpandHome(dir)
+
+	files, err := api.FilesFromPermalink(api.NewClient(workspace), args[0])
+	if err != nil {
+		return fmt.Errorf("couldn't find files: %w", err)
+	}
+
+	for _, file := range files {
+		var progress func(sent, total int64)
+		if !isPiped(os.Stderr) {
+			progress = func(sent, total int64) {
+				fmt.Fprintf(os.Stderr, "\rDownloading %v %d%%", utils.Sanitize(file.Name), sent*100/max(total, 1))
+			}
+		}
+
+		path, err := api.DownloadFile(workspace, file, dir, progress)
+		if progress != nil {
+			fmt.Fprintln(os.Stderr)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println(path)
+
+		if open {
+			if err := utils.Open(path); err != nil {
+				return fmt.Errorf("couldn't open %v: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+func permalinkWorkspace(cfg core.Config, permalink string) string {
+	u, err := url.Parse(permalink)
+	if err != nil {
+		return ""
+	}
+
+	domain := api.NormalizeDomain(u.Hostname())
+	if slices.ContainsFunc(cfg.Workspaces, func(ws core.Workspace) bool { return ws.Domain == domain }) {
+		return domain
+	}
+	return ""
+}
@@ -12,7 +12,8 @@ var InitCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Sets up the config",
 	Long: `Run this command before using HackCLI for the first time.
-	Paste in your slack cookie, select a theme and other initial settingss.`,
+	Paste in your slack cookie, select a theme and other initial settingss.
+	Use --workspace <domain> to add another Slack workspace, e.g. hackcli init --workspace myteam.slack.com`,
 	Run: runInit,
 }
 
@@ -21,7 +22,9 @@ func init() {
 }
 
 func runInit(cmd *cobra.Command, args []string) {
-	program := tea.NewProgram(teaInit.Start(), tea.WithAltScreen())
+	workspace, _ := cmd.Flags().GetString("workspace")
+
+	program := tea.NewProgram(teaInit.Start(workspace), tea.WithAltScreen())
 	_, err := program.Run()
 	if err != nil {
 		panic(fmt.Sprintf("Something went wrong: %v", err))
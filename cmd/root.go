@@ -32,9 +32,13 @@ func runRoot(cmd *cobra.Command, args []string) {
 		initialChannel = args[0]
 	}
 
-	app := channel.Start(initialChannel)
+	offline, _ := cmd.Flags().GetBool("offline")
+	workspace, _ := cmd.Flags().GetString("workspace")
 
-	program := tea.NewProgram(app, tea.WithAltScreen(), tea.WithMouseAllMotion())
+	app := channel.Start(initialChannel, workspace, offline)
+	defer app.Close()
+
+	program := tea.NewProgram(app, tea.WithOutput(app.Output()), tea.WithAltScreen(), tea.WithMouseAllMotion(), tea.WithReportFocus())
 
 	go func() {
 		for msg := range app.MsgChan {
@@ -50,4 +54,6 @@ func runRoot(cmd *cobra.Command, args []string) {
 
 func init() {
 	RootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	RootCmd.Flags().Bool("offline", false, "Browse messages stored on disk without connecting to Slack")
+	RootCmd.PersistentFlags().StringP("workspace", "w", "", "workspace to use, by name or domain (defaults to the last active one)")
 }
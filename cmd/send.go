@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/slack-go/slack"
+	"github.com/spf13/cobra"
+)
+
+var SendCmd = &cobra.Command{
+	Use:   "send <#channel|@user> [message]",
+	Short: "Sends a message without opening the app",
+	Long: `Sends a message to a channel or DM and prints its ts and permalink.
+	When no message is given it is read from stdin, so you can pipe output from scripts, CI jobs or git hooks into Slack.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSend,
+}
+
+func init() {
+	SendCmd.Flags().String("thread", "", "ts of the parent message to reply to")
+	SendCmd.Flags().String("file", "", "path of a file to upload with the message")
+	SendCmd.Flags().Bool("broadcast", false, "also send a thread reply to the channel")
+
+	RootCmd.AddCommand(SendCmd)
+}
+
+func runSend(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	threadTs, _ := cmd.Flags().GetString("thread")
+	filePath, _ := cmd.Flags().GetString("file")
+	broadcast, _ := cmd.Flags().GetBool("broadcast")
+
+	if broadcast && threadTs == "" {
+		return fmt.Errorf("--broadcast only works together with --thread")
+	}
+	if broadcast && filePath != "" {
+		return fmt.Errorf("--broadcast can't be combined with --file")
+	}
+
+	text := strings.Join(args[1:], " ")
+	if len(args) == 1 && (filePath == "" || isPiped(os.Stdin)) {
+		input, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("couldn't read message from stdin: %w", err)
+		}
+		text = strings.TrimRight(string(input), "\n")
+	}
+
+	if strings.TrimSpace(text) == "" && filePath == "" {
+		return fmt.Errorf("message is empty")
+	}
+
+	cfg, err := api.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("couldn't load config, run `hackcli init` first: %w", err)
+	}
+	workspaceName, _ := cmd.Flags().GetString("workspace")
+	workspace, err := api.FindWorkspace(cfg, workspaceName)
+	if err != nil {
+		return err
+	}
+	cache, _ := api.LoadCacheOrEmpty(workspace.Domain)
+	client := api.NewClient(workspace)
+
+	channelID, err := api.ResolveConversation(client, cache, args[0])
+	if err != nil {
+		return err
+	}
+
+	text = api.ResolveMentions(cache, text)
+
+	var ts string
+	if filePath != "" {
+		var progress func(sent, total int64)
+		if !isPiped(os.Stderr) {
+			progress = func(sent, total int64) {
+				fmt.Fprintf(os.Stderr, "\rUploading %v %d%%", filepath.Base(filePath), sent*100/max(total, 1))
+			}
+		}
+
+		file, err := api.UploadFile(client, channelID, threadTs, filePath, text, progress)
+		if progress != nil {
+			fmt.Fprintln(os.Stderr)
+		}
+		if err != nil {
+			return fmt.Errorf("couldn't upload %v: %w", filePath, err)
+		}
+
+		ts = api.FileShareTs(file, channelID)
+		if ts == "" {
+			ts, err = api.FindFileMessageTs(client, file, channelID, threadTs)
+		}
+		if ts == "" {
+			fmt.Fprintf(os.Stderr, "warning: file uploaded but couldn't find its message: %v\n", err)
+			fmt.Println()
+			fmt.Println(file.Permalink)
+			return nil
+		}
+	} else {
+		options := []slack.MsgOption{slack.MsgOptionText(text, false)}
+		if threadTs != "" {
+			options = append(options, slack.MsgOptionTS(threadTs))
+		}
+		if broadcast {
+			options = append(options, slack.MsgOptionBroadcast())
+		}
+
+		api.WithRetry(func() error {
+			_, ts, err = client.PostMessage(channelID, options...)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("couldn't send message: %w", err)
+		}
+	}
+
+	fmt.Println(ts)
+
+	permalink, err := client.GetPermalink(&slack.PermalinkParameters{Channel: channelID, Ts: ts})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: message sent but couldn't get its permalink: %v\n", err)
+		return nil
+	}
+
+	fmt.Println(permalink)
+	return nil
+}
+
+func isPiped(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice == 0
+}
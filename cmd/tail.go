@@ -0,0 +1,304 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/slack-go/slack"
+	"github.com/spf13/cobra"
+)
+
+var TailCmd = &cobra.Command{
+	Use:   "tail <#channel|@user>...",
+	Short: "Streams new messages to stdout",
+	Long: `Prints new messages from the given channels and DMs as they arrive.
+	Use --format json to get one JSON object per line for jq, or --since to print recent history first.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runTail,
+}
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiFaint  = "\x1b[2m"
+	ansiBlue   = "\x1b[34m"
+	ansiPurple = "\x1b[35m"
+	ansiYellow = "\x1b[33m"
+)
+
+type tailer struct {
+	client   core.SlackClient
+	cache    *core.Cache
+	format   string
+	channels map[string]bool
+}
+
+type tailMessage struct {
+	Ts        string `json:"ts"`
+	ThreadTs  string `json:"thread_ts,omitempty"`
+	ChannelID string `json:"channel_id"`
+	Channel   string `json:"channel"`
+	UserID    string `json:"user_id"`
+	User      string `json:"user"`
+	Text      string `json:"text"`
+	RawText   string `json:"raw_text"`
+	SubType   string `json:"subtype,omitempty"`
+}
+
+func init() {
+	TailCmd.Flags().StringP("format", "f", "plain", "output format: plain, color or json")
+	TailCmd.Flags().Duration("since", 0, "print messages from this far back first, e.g. 30m or 2h")
+
+	RootCmd.AddCommand(TailCmd)
+}
+
+func runTail(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	format, _ := cmd.Flags().GetString("format")
+	since, _ := cmd.Flags().GetDuration("since")
+
+	if !slices.Contains([]string{"plain", "color", "json"}, format) {
+		return fmt.Errorf("unknown format %q, use plain, color or json", format)
+	}
+
+	cfg, err := api.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("couldn't load config, run `hackcli init` first: %w", err)
+	}
+	workspaceName, _ := cmd.Flags().GetString("workspace")
+	workspace, err := api.FindWorkspace(cfg, workspaceName)
+	if err != nil {
+		return err
+	}
+	cache, _ := api.LoadCacheOrEmpty(workspace.Domain)
+
+	t := &tailer{
+		client:   api.NewClient(workspace),
+		cache:    cache,
+		format:   format,
+		channels: make(map[string]bool),
+	}
+
+	for _, target := range args {
+		channelID, err := api.ResolveConversation(t.client, t.cache, target)
+		if err != nil {
+			return err
+		}
+		t.channels[channelID] = true
+	}
+
+	if since > 0 {
+		if err := t.printHistory(time.Now().Add(-since)); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	msgChan := make(chan tea.Msg)
+	go api.Websocket{Token: workspace.Token, Cookie: workspace.Cookie}.Run(ctx, msgChan)
+
+	for {
+		var msg tea.Msg
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg = <-msgChan:
+		}
+
+		switch msg := msg.(type) {
+		case core.ConnectionStateMsg:
+			if msg.State == core.Disconnected {
+				fmt.Fprintf(os.Stderr, "disconnected, retrying in %v\n", msg.RetryIn.Round(time.Second))
+			}
+		case core.HandleEventMsg:
+			ev, ok := msg.Event.(*api.MessageEvent)
+			if !ok || !t.channels[ev.Channel] || !isTailedSubType(ev.SubType) {
+				continue
+			}
+
+			t.print(ev.Channel, slack.Message{Msg: slack.Msg{
+				Timestamp:       ev.Timestamp,
+				ThreadTimestamp: ev.ThreadTimestamp,
+				User:            ev.User,
+				Text:            ev.Text,
+				SubType:         ev.SubType,
+			}})
+		}
+	}
+}
+
+func (t *tailer) printHistory(oldest time.Time) error {
+	type historyItem struct {
+		channelID string
+		message   slack.Message
+	}
+	var history []historyItem
+
+	for channelID := range t.channels {
+		params := &slack.GetConversationHistoryParameters{
+			ChannelID: channelID,
+			Oldest:    strconv.FormatInt(oldest.Unix(), 10),
+			Limit:     200,
+		}
+
+		messages, err := api.Paginate(func(cursor string) ([]slack.Message, string, error) {
+			params.Cursor = cursor
+			res, err := t.client.GetConversationHistory(params)
+			if err != nil {
+				return nil, "", err
+			}
+			return res.Messages, res.ResponseMetaData.NextCursor, nil
+		})
+		if err != nil {
+			return fmt.Errorf("couldn't load history of %v: %w", t.channelLabel(channelID), err)
+		}
+
+		for _, mes := range messages {
+			if isTailedSubType(mes.SubType) {
+				history = append(history, historyItem{channelID: channelID, message: mes})
+			}
+		}
+	}
+
+	slices.SortFunc(history, func(first, second historyItem) int {
+		return strings.Compare(first.message.Timestamp, second.message.Timestamp)
+	})
+
+	for _, item := range history {
+		t.print(item.channelID, item.message)
+	}
+	return nil
+}
+
+func isTailedSubType(subType string) bool {
+	switch subType {
+	case "", "thread_broadcast", "file_share", "bot_message", "me_message":
+		return true
+	}
+	return false
+}
+
+func (t *tailer) print(channelID string, mes slack.Message) {
+	userName := mes.Username
+	if mes.User != "" {
+		userName = t.userName(mes.User)
+	}
+
+	out := tailMessage{
+		Ts:        mes.Timestamp,
+		ChannelID: channelID,
+		Channel:   t.channelLabel(channelID),
+		UserID:    mes.User,
+		User:      userName,
+		Text:      utils.PlainMrkdwn(mes.Text, t.userName, t.channelName, t.cache.Emoji),
+		RawText:   mes.Text,
+		SubType:   mes.SubType,
+	}
+	if mes.ThreadTimestamp != mes.Timestamp {
+		out.ThreadTs = mes.ThreadTimestamp
+	}
+
+	if t.format == "json" {
+		line, err := json.Marshal(out)
+		if err == nil {
+			fmt.Println(string(line))
+		}
+		return
+	}
+
+	timestamp := out.Ts
+	if sec, err := strconv.ParseInt(strings.Split(out.Ts, ".")[0], 10, 64); err == nil {
+		timestamp = time.Unix(sec, 0).Format("2006-01-02 15:04:05")
+	}
+
+	location := utils.Sanitize(out.Channel)
+	if out.ThreadTs != "" {
+		location += " (thread " + out.ThreadTs + ")"
+	}
+	user := utils.Sanitize(out.User)
+	text := strings.ReplaceAll(utils.Sanitize(out.Text), "\n", "\n    ")
+
+	if t.format == "color" {
+		fmt.Printf("%s%s%s %s%s%s %s%s%s%s %s\n",
+			ansiFaint, timestamp, ansiReset,
+			ansiBlue, location, ansiReset,
+			ansiBold, ansiPurple, user, ansiReset,
+			highlightMentions(text))
+		return
+	}
+
+	fmt.Printf("%s %s %s: %s\n", timestamp, location, user, text)
+}
+
+func highlightMentions(text string) string {
+	words := strings.Split(text, " ")
+	for i, word := range words {
+		if strings.HasPrefix(word, "@") || strings.HasPrefix(word, "#") {
+			words[i] = ansiYellow + word + ansiReset
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func (t *tailer) userName(userID string) string {
+	if user, ok := t.cache.Users[userID]; ok && user.Name != "..." {
+		return user.Name
+	}
+
+	user, err := api.GetUserInfo(t.client, userID)
+	if err != nil {
+		return userID
+	}
+
+	name := api.DisplayName(user)
+	t.cache.Users[userID] = &core.User{ID: userID, Name: name}
+	return name
+}
+
+func (t *tailer) channelName(channelID string) string {
+	if conv, ok := t.cache.Conversations[channelID]; ok {
+		if strings.HasPrefix(channelID, "D") && conv.User.ID != "" {
+			return t.userName(conv.User.ID)
+		}
+		if conv.Name != "" && conv.Name != "..." {
+			return conv.Name
+		}
+	}
+
+	channel, err := t.client.GetConversationInfo(&slack.GetConversationInfoInput{ChannelID: channelID})
+	if err != nil {
+		return channelID
+	}
+
+	conv := &core.Conversation{ID: channelID, Name: channel.Name, IsMember: channel.IsMember}
+	if channel.IsIM {
+		conv.User = core.User{ID: channel.User, Name: t.userName(channel.User)}
+	}
+	t.cache.Conversations[channelID] = conv
+
+	if channel.IsIM {
+		return conv.User.Name
+	}
+	return conv.Name
+}
+
+func (t *tailer) channelLabel(channelID string) string {
+	if strings.HasPrefix(channelID, "D") {
+		return "@" + t.channelName(channelID)
+	}
+	return "#" + t.channelName(channelID)
+}
@@ -0,0 +1,41 @@
+package core
+
+import "encoding/json"
+
+func (t *BlockText) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		t.Type = ""
+		return json.Unmarshal(data, &t.Text)
+	}
+
+	type alias BlockText
+	return json.Unmarshal(data, (*alias)(t))
+}
+
+type blockTextStyle struct {
+	Bold   bool `json:"bold,omitempty"`
+	Italic bool `json:"italic,omitempty"`
+	Strike bool `json:"strike,omitempty"`
+	Code   bool `json:"code,omitempty"`
+}
+
+func (s *BlockStyle) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		*s = BlockStyle{}
+		return json.Unmarshal(data, &s.Name)
+	}
+
+	var style blockTextStyle
+	if err := json.Unmarshal(data, &style); err != nil {
+		return err
+	}
+	*s = BlockStyle{Bold: style.Bold, Italic: style.Italic, Strike: style.Strike, Code: style.Code}
+	return nil
+}
+
+func (s BlockStyle) MarshalJSON() ([]byte, error) {
+	if s.Name != "" {
+		return json.Marshal(s.Name)
+	}
+	return json.Marshal(blockTextStyle{Bold: s.Bold, Italic: s.Italic, Strike: s.Strike, Code: s.Code})
+}
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"context"
+	"image"
 	"sync"
 	"time"
 
@@ -9,14 +11,40 @@ import (
 )
 
 type Config struct {
+	Token           string             `json:"token,omitempty"`
+	Cookie          string             `json:"cookie,omitempty"`
+	Theme           string             `json:"theme"`
+	Workspaces      []Workspace        `json:"workspaces"`
+	ActiveWorkspace string             `json:"active_workspace"`
+	Notifications   NotificationConfig `json:"notifications"`
+	Images          string             `json:"images,omitempty"`
+	Downloads       string             `json:"downloads,omitempty"`
+}
+
+type NotificationConfig struct {
+	Backend      string   `json:"backend"`
+	Command      []string `json:"command,omitempty"`
+	Keywords     []string `json:"keywords,omitempty"`
+	Muted        []string `json:"muted,omitempty"`
+	DoNotDisturb bool     `json:"do_not_disturb"`
+}
+
+type Workspace struct {
+	Name   string `json:"name"`
+	Domain string `json:"domain"`
 	Token  string `json:"token"`
 	Cookie string `json:"cookie"`
-	Theme  string `json:"theme"`
 }
 
 type Cache struct {
 	Users         map[string]*User
 	Conversations map[string]*Conversation
+	Emoji         map[string]string
+	EmojiUsage    map[string]int
+	Threads       map[string]*ThreadInfo
+	Activity      []*ActivityItem
+	UserID        string
+	Workspace     string `json:"-"`
 }
 
 type User struct {
@@ -32,6 +60,8 @@ type Conversation struct {
 	LastRead      string `json:"last_read"`
 	LatestMessage string `json:"latest_message"`
 	IsMember      bool   `json:"is_member"`
+	UnreadCount   int    `json:"unread_count"`
+	MentionCount  int    `json:"mention_count"`
 }
 
 type App struct {
@@ -39,18 +69,59 @@ type App struct {
 	Config         Config
 	Cache          *Cache
 	InitialLoading bool
-	Client         *slack.Client
+	Client         SlackClient
+	Events         EventSource
 	MsgChan        chan tea.Msg
 	CurrentChannel string
+	Offline        bool
 	Mutex          sync.RWMutex
 }
 
+type SlackClient interface {
+	AuthTest() (*slack.AuthTestResponse, error)
+	GetConversationHistory(params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error)
+	GetConversationReplies(params *slack.GetConversationRepliesParameters) ([]slack.Message, bool, string, error)
+	GetConversationInfo(input *slack.GetConversationInfoInput) (*slack.Channel, error)
+	GetConversations(params *slack.GetConversationsParameters) ([]slack.Channel, string, error)
+	GetConversationsForUser(params *slack.GetConversationsForUserParameters) ([]slack.Channel, string, error)
+	OpenConversation(params *slack.OpenConversationParameters) (*slack.Channel, bool, bool, error)
+	JoinConversation(channelID string) (*slack.Channel, string, []string, error)
+	LeaveConversation(channelID string) (bool, error)
+	MarkConversation(channel, ts string) error
+	PostMessage(channelID string, options ...slack.MsgOption) (string, string, error)
+	SendMessage(channel string, options ...slack.MsgOption) (string, string, string, error)
+	UpdateMessage(channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error)
+	DeleteMessage(channel, messageTimestamp string) (string, string, error)
+	GetPermalink(params *slack.PermalinkParameters) (string, error)
+	AddReaction(name string, item slack.ItemRef) error
+	RemoveReaction(name string, item slack.ItemRef) error
+	SearchMessages(query string, params slack.SearchParameters) (*slack.SearchMessages, error)
+	GetUserInfo(user string) (*slack.User, error)
+	GetUserPresence(user string) (*slack.UserPresence, error)
+	GetEmoji() (map[string]string, error)
+	UploadFileV2(params slack.UploadFileV2Parameters) (*slack.FileSummary, error)
+	GetFileInfo(fileID string, count, page int) (*slack.File, []slack.Comment, *slack.Paging, error)
+}
+
+type EventSource interface {
+	Run(ctx context.Context, msgChan chan tea.Msg)
+}
+
+type ConnectionState int
+
+const (
+	Connecting ConnectionState = iota
+	Connected
+	Disconnected
+)
+
 type Message struct {
 	Ts          string
 	ThreadId    string
 	User        string
 	Content     string
 	Attachments []Attachment
+	Blocks      []Block
 	Files       []File
 	Reactions   map[string][]string
 	SubType     string
@@ -64,19 +135,97 @@ type Reaction struct {
 }
 
 type Attachment struct {
-	ImageURL    string `json:"image_url,omitempty"`
-	ThumbURL    string `json:"thumb_url,omitempty"`
-	FromURL     string `json:"from_url,omitempty"`
-	OriginalURL string `json:"original_url,omitempty"`
+	Color       string            `json:"color,omitempty"`
+	Fallback    string            `json:"fallback,omitempty"`
+	Pretext     string            `json:"pretext,omitempty"`
+	AuthorName  string            `json:"author_name,omitempty"`
+	AuthorLink  string            `json:"author_link,omitempty"`
+	Title       string            `json:"title,omitempty"`
+	TitleLink   string            `json:"title_link,omitempty"`
+	Text        string            `json:"text,omitempty"`
+	Fields      []AttachmentField `json:"fields,omitempty"`
+	Footer      string            `json:"footer,omitempty"`
+	ServiceName string            `json:"service_name,omitempty"`
+	ImageURL    string            `json:"image_url,omitempty"`
+	ThumbURL    string            `json:"thumb_url,omitempty"`
+	FromURL     string            `json:"from_url,omitempty"`
+	OriginalURL string            `json:"original_url,omitempty"`
+}
+
+type Block struct {
+	Type      string         `json:"type"`
+	Text      *BlockText     `json:"text,omitempty"`
+	Fields    []BlockText    `json:"fields,omitempty"`
+	Accessory *BlockElement  `json:"accessory,omitempty"`
+	Elements  []BlockElement `json:"elements,omitempty"`
+	ImageURL  string         `json:"image_url,omitempty"`
+	AltText   string         `json:"alt_text,omitempty"`
+	Title     *BlockText     `json:"title,omitempty"`
+}
+
+type BlockText struct {
+	Type string `json:"type,omitempty"`
+	Text string `json:"text"`
+}
+
+type BlockStyle struct {
+	Name   string
+	Bold   bool
+	Italic bool
+	Strike bool
+	Code   bool
+}
+
+type BlockElement struct {
+	Type          string         `json:"type"`
+	Text          *BlockText     `json:"text,omitempty"`
+	Style         *BlockStyle    `json:"style,omitempty"`
+	URL           string         `json:"url,omitempty"`
+	ActionID      string         `json:"action_id,omitempty"`
+	Value         string         `json:"value,omitempty"`
+	Placeholder   *BlockText     `json:"placeholder,omitempty"`
+	Options       []BlockOption  `json:"options,omitempty"`
+	InitialOption *BlockOption   `json:"initial_option,omitempty"`
+	ImageURL      string         `json:"image_url,omitempty"`
+	AltText       string         `json:"alt_text,omitempty"`
+	Elements      []BlockElement `json:"elements,omitempty"`
+	Indent        int            `json:"indent,omitempty"`
+	Name          string         `json:"name,omitempty"`
+	UserID        string         `json:"user_id,omitempty"`
+	ChannelID     string         `json:"channel_id,omitempty"`
+	UsergroupID   string         `json:"usergroup_id,omitempty"`
+	Range         string         `json:"range,omitempty"`
+	Fallback      string         `json:"fallback,omitempty"`
+	Offset        int            `json:"offset,omitempty"`
+}
+
+type BlockOption struct {
+	Text  *BlockText `json:"text,omitempty"`
+	Value string     `json:"value,omitempty"`
+}
+
+type AttachmentField struct {
+	Title string `json:"title,omitempty"`
+	Value string `json:"value,omitempty"`
+	Short bool   `json:"short,omitempty"`
 }
 
 type File struct {
+	ID         string `json:"id,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Title      string `json:"title,omitempty"`
+	Mimetype   string `json:"mimetype,omitempty"`
+	Size       int    `json:"size,omitempty"`
 	URLPrivate string `json:"url_private,omitempty"`
 	Permalink  string `json:"permalink,omitempty"`
+	Thumb360   string `json:"thumb_360,omitempty"`
+	Thumb480   string `json:"thumb_480,omitempty"`
 }
 
 type ChannelSelectedMsg struct {
-	Id string
+	Id       string
+	Ts       string
+	ThreadTs string
 }
 
 type NewMessageMsg struct {
@@ -84,8 +233,10 @@ type NewMessageMsg struct {
 }
 
 type EditedMessageMsg struct {
-	Ts      string
-	Content string
+	Ts          string
+	Content     string
+	Attachments []Attachment
+	Blocks      []Block
 }
 
 type DeletedMessageMsg struct {
@@ -93,11 +244,18 @@ type DeletedMessageMsg struct {
 }
 
 type HistoryLoadedMsg struct {
-	Messages []Message
-	LatestTs string
+	ChannelID string
+	Messages  []Message
+	LatestTs  string
+	Older     bool
+	Newer     bool
+	HasMore   bool
+	HasNewer  bool
+	FocusTs   string
 }
 
 type UserInfoLoadedMsg struct {
+	Workspace string
 	User      *slack.User
 	IsHistory bool
 }
@@ -130,6 +288,7 @@ type ReactionScrollMsg struct {
 }
 
 type ChannelReadMsg struct {
+	Workspace string
 	ChannelID string
 	LatestTs  string
 	LastRead  string
@@ -141,7 +300,12 @@ type PresenceChangedMsg struct {
 }
 
 type ThreadLoadedMsg struct {
-	Messages []Message
+	ChannelID string
+	ThreadTs  string
+	Messages  []Message
+	Cursor    string
+	More      bool
+	HasMore   bool
 }
 
 type ChannelJoinedMsg struct {
@@ -153,6 +317,7 @@ type ChannelLeftMsg struct {
 }
 
 type ChannelInfoLoadedMsg struct {
+	Workspace string
 	Channel   *slack.Channel
 	LatestMes string
 }
@@ -164,14 +329,160 @@ type WaitMsg struct {
 
 type CloseErrorPopupMsg struct{}
 
+type ConnectionStateMsg struct {
+	State       ConnectionState
+	Reconnected bool
+	RetryIn     time.Duration
+}
+
+type RetryTickMsg struct {
+	RetryAt time.Time
+}
+
+type WorkspaceEventMsg struct {
+	Workspace string
+	Msg       tea.Msg
+}
+
+type SaveCacheMsg struct{}
+
 type InsertChannelInSidebarMsg struct {
 	ChannelName string
 	ChannelID   string
 }
 
+type EmojiLoadedMsg struct {
+	Workspace string
+	Emoji     map[string]string
+}
+
+type LoadingProgressMsg struct {
+	Workspace string
+	Stage     string
+	Done      int
+	Total     int
+}
+
+type InsertDMInSidebarMsg struct {
+	DM Conversation
+}
+
 type FetchedCacheMsg struct {
+	Workspace       string
 	Users           map[string]*User
 	Conversations   map[string]*Conversation
 	SidebarChannels []Conversation
 	SidebarDms      []Conversation
 }
+
+type ThreadInfo struct {
+	ChannelID  string
+	ThreadTs   string
+	ParentUser string
+	ParentText string
+	LatestTs   string
+	LatestUser string
+	LatestText string
+	LastRead   string
+}
+
+type ActivityItem struct {
+	Kind      string
+	ChannelID string
+	Ts        string
+	ThreadTs  string
+	User      string
+	Text      string
+	Reaction  string
+	EventTs   string
+	Read      bool
+}
+
+type ActivityFoundMsg struct {
+	Workspace string
+	Items     []ActivityItem
+}
+
+type UploadProgressMsg struct {
+	Name  string
+	Sent  int64
+	Total int64
+}
+
+type UploadFinishedMsg struct {
+	Name string
+	Err  error
+}
+
+type DownloadProgressMsg struct {
+	Name  string
+	Sent  int64
+	Total int64
+}
+
+type DownloadFinishedMsg struct {
+	Name string
+	Path string
+	Open bool
+	Err  error
+}
+
+type ImageLoadedMsg struct {
+	URL   string
+	Image image.Image
+	Err   error
+}
+
+type ImagesFetchedMsg struct{}
+
+type BrowsedChannel struct {
+	ID         string
+	Name       string
+	Topic      string
+	Purpose    string
+	NumMembers int
+	IsMember   bool
+}
+
+type ChannelsListedMsg struct {
+	Workspace string
+	Cursor    string
+	Channels  []BrowsedChannel
+	Next      string
+	Err       error
+}
+
+type ChannelPreviewMsg struct {
+	Workspace string
+	ChannelID string
+	Messages  []Message
+	Err       error
+}
+
+type ThreadsFoundMsg struct {
+	Workspace string
+	Threads   []ThreadInfo
+}
+
+type ThreadParentLoadedMsg struct {
+	ChannelID string
+	ThreadTs  string
+	User      string
+	Text      string
+}
+
+type SearchResult struct {
+	Message     Message
+	ChannelID   string
+	ChannelName string
+	Permalink   string
+}
+
+type SearchResultsMsg struct {
+	Query     string
+	Results   []SearchResult
+	Page      int
+	PageCount int
+	Total     int
+	Err       error
+}
@@ -0,0 +1,7 @@
+//go:build !unix
+
+package images
+
+func CellSize() (width, height int) {
+	return defaultCellWidth, defaultCellHeight
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package images
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func CellSize() (width, height int) {
+	size, err := unix.IoctlGetWinsize(int(os.Stdout.Fd()), unix.TIOCGWINSZ)
+	if err != nil || size.Col == 0 || size.Row == 0 || size.Xpixel == 0 || size.Ypixel == 0 {
+		return defaultCellWidth, defaultCellHeight
+	}
+	return max(1, int(size.Xpixel/size.Col)), max(1, int(size.Ypixel/size.Row))
+}
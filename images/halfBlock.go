@@ -0,0 +1,37 @@
+package images
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+)
+
+func halfBlock(img image.Image, cols, rows int) []string {
+	scaled := Resize(img, cols, rows*2)
+
+	lines := make([]string, rows)
+	for y := range rows {
+		var b strings.Builder
+		var lastTop, lastBottom color.RGBA
+
+		for x := range cols {
+			top := opaque(scaled.At(x, y*2))
+			bottom := opaque(scaled.At(x, y*2+1))
+
+			if x == 0 || top != lastTop || bottom != lastBottom {
+				fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%d;48;2;%d;%d;%dm", top.R, top.G, top.B, bottom.R, bottom.G, bottom.B)
+				lastTop, lastBottom = top, bottom
+			}
+			b.WriteString("▀")
+		}
+		b.WriteString("\x1b[39;49m")
+		lines[y] = b.String()
+	}
+	return lines
+}
+
+func opaque(c color.Color) color.RGBA {
+	r, g, b, _ := c.RGBA()
+	return color.RGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: 0xff}
+}
@@ -0,0 +1,137 @@
+package images
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"strings"
+)
+
+type Protocol int
+
+const (
+	Off Protocol = iota
+	HalfBlock
+	Kitty
+	Sixel
+)
+
+const (
+	maxThumbnailSize  = 640
+	maxDecodePixels   = 20_000_000
+	defaultCellWidth  = 10
+	defaultCellHeight = 20
+)
+
+func Detect(setting string) Protocol {
+	switch setting {
+	case "off":
+		return Off
+	case "halfblock":
+		return HalfBlock
+	case "kitty":
+		return Kitty
+	case "sixel":
+		return Sixel
+	}
+
+	if os.Getenv("TMUX") != "" || strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		return HalfBlock
+	}
+
+	term := os.Getenv("TERM")
+	program := os.Getenv("TERM_PROGRAM")
+
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || program == "ghostty" || term == "xterm-ghostty":
+		return Kitty
+	case strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "mlterm") || strings.HasPrefix(term, "contour") ||
+		program == "WezTerm" || program == "iTerm.app" || os.Getenv("KONSOLE_VERSION") != "" || os.Getenv("WT_SESSION") != "":
+		return Sixel
+	}
+	return HalfBlock
+}
+
+func Decode(r io.Reader) (image.Image, error) {
+	var head bytes.Buffer
+	config, _, err := image.DecodeConfig(io.TeeReader(r, &head))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxDecodePixels {
+		return nil, fmt.Errorf("image is too large to preview (%dx%d)", config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(io.MultiReader(&head, r))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() <= maxThumbnailSize && bounds.Dy() <= maxThumbnailSize {
+		return img, nil
+	}
+
+	if bounds.Dx() >= bounds.Dy() {
+		return Resize(img, maxThumbnailSize, max(1, bounds.Dy()*maxThumbnailSize/bounds.Dx())), nil
+	}
+	return Resize(img, max(1, bounds.Dx()*maxThumbnailSize/bounds.Dy()), maxThumbnailSize), nil
+}
+
+func Size(img image.Image, maxCols, maxRows int) (cols, rows int) {
+	cellWidth, cellHeight := CellSize()
+	bounds := img.Bounds()
+	width, height := max(1, bounds.Dx()), max(1, bounds.Dy())
+	maxRows = min(maxRows, len(kittyDiacritics))
+
+	cols = min(maxCols, (width+cellWidth-1)/cellWidth)
+	rows = (cols*cellWidth*height + width*cellHeight - 1) / (width * cellHeight)
+
+	if rows > maxRows {
+		rows = maxRows
+		cols = min(maxCols, rows*cellHeight*width/(height*cellWidth))
+	}
+	return max(1, cols), max(1, rows)
+}
+
+func Resize(img image.Image, width, height int) image.Image {
+	bounds := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := range height {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+
+		for x := range width {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, b, a = r+pr, g+pg, b+pb, a+pa
+					n++
+				}
+			}
+			out.SetRGBA(x, y, color.RGBA{R: uint8(r / n >> 8), G: uint8(g / n >> 8), B: uint8(b / n >> 8), A: uint8(a / n >> 8)})
+		}
+	}
+	return out
+}
+
+func Render(protocol Protocol, id uint32, img image.Image, cols, rows int) []string {
+	switch protocol {
+	case Kitty:
+		return kittyPlaceholder(id, cols, rows)
+	case Sixel:
+		return sixelBlock(img, cols, rows)
+	}
+	return halfBlock(img, cols, rows)
+}
@@ -0,0 +1,34 @@
+package images
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestDecodeRejectsHugeImages(t *testing.T) {
+	header := []byte("GIF89a\xff\xff\xff\xff\x00\x00\x00")
+	if _, err := Decode(bytes.NewReader(header)); err == nil {
+		t.Fatal("decoded a 65535x65535 image")
+	}
+}
+
+func TestDecodeShrinksLargeImages(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 1280, 320))
+	img.Set(0, 0, color.White)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bounds := decoded.Bounds(); bounds.Dx() != maxThumbnailSize || bounds.Dy() != 160 {
+		t.Fatalf("got %dx%d, want %dx160", bounds.Dx(), bounds.Dy(), maxThumbnailSize)
+	}
+}
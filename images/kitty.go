@@ -0,0 +1,76 @@
+package images
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/png"
+	"strings"
+)
+
+const (
+	placeholder    = "\U0010EEEE"
+	kittyChunkSize = 4096
+)
+
+var kittyDiacritics = []rune{
+	0x0305, 0x030D, 0x030E, 0x0310, 0x0312, 0x033D, 0x033E, 0x033F, 0x0346, 0x034A,
+	0x034B, 0x034C, 0x0350, 0x0351, 0x0352, 0x0357, 0x035B, 0x0363, 0x0364, 0x0365,
+	0x0366, 0x0367, 0x0368, 0x0369, 0x036A, 0x036B, 0x036C, 0x036D, 0x036E, 0x036F,
+}
+
+func ID(key string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return max(1, h.Sum32()&0xffffff)
+}
+
+func KittyTransmit(id uint32, img image.Image, cols, rows int) (string, error) {
+	cellWidth, cellHeight := CellSize()
+	bounds := img.Bounds()
+	if cols*cellWidth < bounds.Dx() || rows*cellHeight < bounds.Dy() {
+		width := min(bounds.Dx(), cols*cellWidth)
+		height := min(bounds.Dy(), rows*cellHeight)
+		if width*bounds.Dy() > height*bounds.Dx() {
+			width = max(1, height*bounds.Dx()/bounds.Dy())
+		} else {
+			height = max(1, width*bounds.Dy()/bounds.Dx())
+		}
+		img = Resize(img, width, height)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	var b strings.Builder
+	for i := 0; i < len(data); i += kittyChunkSize {
+		chunk := data[i:min(len(data), i+kittyChunkSize)]
+		more := 0
+		if i+kittyChunkSize < len(data) {
+			more = 1
+		}
+
+		if i == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,U=1,q=2,f=100,i=%d,c=%d,r=%d,m=%d;%v\x1b\\", id, cols, rows, more, chunk)
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%v\x1b\\", more, chunk)
+		}
+	}
+	return b.String(), nil
+}
+
+func kittyPlaceholder(id uint32, cols, rows int) []string {
+	color := fmt.Sprintf("\x1b[38;2;%d;%d;%dm", id>>16&0xff, id>>8&0xff, id&0xff)
+
+	lines := make([]string, min(rows, len(kittyDiacritics)))
+	for row := range lines {
+		lines[row] = color + placeholder + string(kittyDiacritics[row]) + string(kittyDiacritics[0]) +
+			strings.Repeat(placeholder, cols-1) + "\x1b[39m"
+	}
+	return lines
+}
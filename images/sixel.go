@@ -0,0 +1,129 @@
+package images
+
+import (
+	"fmt"
+	"image"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var sixelAnchor = regexp.MustCompile("\x1b7(?:\x1b\\[(\\d+)A)?\x1b\\[\\d+D\x1bP[^\x1b]*\x1b\\\\\x1b8")
+
+func sixelBlock(img image.Image, cols, rows int) []string {
+	cellWidth, cellHeight := CellSize()
+	bounds := img.Bounds()
+
+	width, height := cols*cellWidth, rows*cellHeight
+	if width*bounds.Dy() > height*bounds.Dx() {
+		width = max(1, height*bounds.Dx()/bounds.Dy())
+	} else {
+		height = max(1, width*bounds.Dy()/bounds.Dx())
+	}
+
+	lines := make([]string, rows)
+	for i := range lines {
+		lines[i] = strings.Repeat(" ", cols)
+	}
+
+	var up string
+	if rows > 1 {
+		up = fmt.Sprintf("\x1b[%dA", rows-1)
+	}
+	lines[rows-1] += "\x1b7" + up + fmt.Sprintf("\x1b[%dD", cols) + encodeSixel(Resize(img, width, height)) + "\x1b8"
+	return lines
+}
+
+func ClipSixel(view string) string {
+	if !strings.Contains(view, "\x1bP") {
+		return view
+	}
+
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		lines[i] = sixelAnchor.ReplaceAllStringFunc(line, func(anchor string) string {
+			up, _ := strconv.Atoi(sixelAnchor.FindStringSubmatch(anchor)[1])
+			if i-up < 0 {
+				return ""
+			}
+			return anchor
+		})
+	}
+	return strings.Join(lines, "\n")
+}
+
+func encodeSixel(img image.Image) string {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	pixels := make([]int, width*height)
+	used := make([]bool, 216)
+	for y := range height {
+		for x := range width {
+			c := opaque(img.At(bounds.Min.X+x, bounds.Min.Y+y))
+			index := int(c.R)*6/256*36 + int(c.G)*6/256*6 + int(c.B)*6/256
+			pixels[y*width+x] = index
+			used[index] = true
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\x1bP0;1;0q\"1;1;%d;%d", width, height)
+	for index, ok := range used {
+		if ok {
+			fmt.Fprintf(&b, "#%d;2;%d;%d;%d", index, index/36*20, index/6%6*20, index%6*20)
+		}
+	}
+
+	row := make([]byte, width)
+	for top := 0; top < height; top += 6 {
+		first := true
+		for index, ok := range used {
+			if !ok {
+				continue
+			}
+
+			present := false
+			for x := range width {
+				var bits byte
+				for dy := 0; dy < 6 && top+dy < height; dy++ {
+					if pixels[(top+dy)*width+x] == index {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = 63 + bits
+				present = present || bits != 0
+			}
+			if !present {
+				continue
+			}
+
+			if !first {
+				b.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&b, "#%d", index)
+			writeSixelRuns(&b, row)
+		}
+		b.WriteByte('-')
+	}
+
+	b.WriteString("\x1b\\")
+	return b.String()
+}
+
+func writeSixelRuns(b *strings.Builder, row []byte) {
+	for x := 0; x < len(row); {
+		run := 1
+		for x+run < len(row) && row[x+run] == row[x] {
+			run++
+		}
+
+		if run > 3 {
+			fmt.Fprintf(b, "!%d%c", run, row[x])
+		} else {
+			b.WriteString(strings.Repeat(string(row[x]), run))
+		}
+		x += run
+	}
+}
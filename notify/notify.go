@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/utils"
+)
+
+const maxBodyLength = 200
+
+type Notification struct {
+	Title string
+	Body  string
+}
+
+type Backend interface {
+	Send(n Notification) error
+}
+
+type Bell struct {
+	Out io.Writer
+}
+
+func (b Bell) Send(n Notification) error {
+	_, err := io.WriteString(b.Out, "\a")
+	return err
+}
+
+type OSC9 struct {
+	Out io.Writer
+}
+
+func (o OSC9) Send(n Notification) error {
+	_, err := fmt.Fprintf(o.Out, "\x1b]9;%v: %v\a", oneLine(n.Title), oneLine(n.Body))
+	return err
+}
+
+type OSC777 struct {
+	Out io.Writer
+}
+
+func (o OSC777) Send(n Notification) error {
+	title := strings.ReplaceAll(oneLine(n.Title), ";", ",")
+	_, err := fmt.Fprintf(o.Out, "\x1b]777;notify;%v;%v\a", title, oneLine(n.Body))
+	return err
+}
+
+type Command struct {
+	Args []string
+}
+
+func (c Command) Send(n Notification) error {
+	if len(c.Args) == 0 {
+		return fmt.Errorf("no notification command configured")
+	}
+
+	args := c.expand(n)
+	cmd := exec.Command(args[0], args[1:]...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+func (c Command) expand(n Notification) []string {
+	replacer := strings.NewReplacer("{title}", utils.Sanitize(n.Title), "{body}", utils.Sanitize(n.Body))
+
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = replacer.Replace(arg)
+	}
+	return args
+}
+
+type None struct{}
+
+func (None) Send(n Notification) error { return nil }
+
+func New(cfg core.NotificationConfig, out io.Writer) Backend {
+	switch cfg.Backend {
+	case "none":
+		return None{}
+	case "osc9":
+		return OSC9{Out: out}
+	case "osc777":
+		return OSC777{Out: out}
+	case "command":
+		args := cfg.Command
+		if len(args) == 0 {
+			args = []string{"notify-send", "--app-name=HackCLI", "--", "{title}", "{body}"}
+		}
+		return Command{Args: args}
+	}
+	return Bell{Out: out}
+}
+
+func ShouldNotify(cfg core.NotificationConfig, self, channelID, text string) bool {
+	if cfg.DoNotDisturb || slices.Contains(cfg.Muted, channelID) {
+		return false
+	}
+
+	if strings.HasPrefix(channelID, "D") {
+		return true
+	}
+
+	if utils.MentionsUser(text, self) || utils.MentionsEveryone(text) {
+		return true
+	}
+
+	lower := strings.ToLower(text)
+	return slices.ContainsFunc(cfg.Keywords, func(keyword string) bool {
+		return keyword != "" && strings.Contains(lower, strings.ToLower(keyword))
+	})
+}
+
+func Body(text string) string {
+	text = utils.Sanitize(text)
+	if len([]rune(text)) > maxBodyLength {
+		text = string([]rune(text)[:maxBodyLength-1]) + "…"
+	}
+	return text
+}
+
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(utils.Sanitize(text)), " ")
+}
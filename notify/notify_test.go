@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Jan-Kur/HackCLI/core"
+)
+
+func TestDefaultCommandEndsOptions(t *testing.T) {
+	b := New(core.NotificationConfig{Backend: "command"}, nil)
+	backend, ok := b.(Command)
+	if !ok {
+		t.Fatalf("New returned %T, want Command", b)
+	}
+
+	args := backend.expand(Notification{Title: "-u critical", Body: "--icon=/tmp/x.png"})
+	end := slices.Index(args, "--")
+	if end == -1 {
+		t.Fatalf("args %q have no --", args)
+	}
+	if want := []string{"-u critical", "--icon=/tmp/x.png"}; !slices.Equal(args[end+1:], want) {
+		t.Errorf("args after -- = %q, want %q", args[end+1:], want)
+	}
+}
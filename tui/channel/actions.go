@@ -0,0 +1,124 @@
+package channel
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/slack-go/slack"
+)
+
+type actionRow struct {
+	element core.BlockElement
+	option  *core.BlockOption
+}
+
+func actionRows(mes core.Message) []actionRow {
+	var rows []actionRow
+	for _, element := range interactiveElements(mes) {
+		rows = append(rows, actionRow{element: element})
+		for i := range element.Options {
+			rows = append(rows, actionRow{element: element, option: &element.Options[i]})
+		}
+	}
+	return rows
+}
+
+func (a *app) openActions(mes core.Message) {
+	rows := actionRows(mes)
+	if len(rows) == 0 {
+		return
+	}
+
+	a.popup.popupType = PopupActions
+	a.popup.targetMes = mes
+	a.popup.actions = rows
+	a.popup.actionStatus = ""
+	a.popup.input.SetWidth(50)
+	a.popup.input.Blur()
+	a.popup.isVisible = true
+	a.popup.selected = 0
+}
+
+func (a *app) actionsKeybinds(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k", "ctrl+p":
+		if a.popup.selected > 0 {
+			a.popup.selected--
+		}
+	case "down", "j", "ctrl+n":
+		if a.popup.selected < len(a.popup.actions)-1 {
+			a.popup.selected++
+		}
+	case "enter":
+		row := a.popup.actions[a.popup.selected]
+		if row.option == nil && row.element.URL != "" {
+			if !isWebURL(row.element.URL) {
+				a.popup.actionStatus = "Only http and https links can be opened"
+				return a, nil
+			}
+			if err := utils.Open(row.element.URL); err != nil {
+				a.popup.actionStatus = "Couldn't open link: " + err.Error()
+			} else {
+				a.popup.actionStatus = "Opened " + utils.Sanitize(row.element.URL)
+			}
+			return a, nil
+		}
+		a.popup.actionStatus = "Apps only get button clicks from Slack itself, press O to open the message there"
+	case "o":
+		a.popup.actionStatus = "Opening the message in Slack…"
+		go a.openInSlack(a.CurrentChannel, a.popup.targetMes.Ts)
+	}
+	return a, nil
+}
+
+func isWebURL(link string) bool {
+	u, err := url.Parse(link)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func (a *app) openInSlack(channelID, ts string) {
+	permalink, err := a.Client.GetPermalink(&slack.PermalinkParameters{Channel: channelID, Ts: ts})
+	if err == nil {
+		err = utils.Open(permalink)
+	}
+	if err != nil {
+		a.showErrorPopup("Couldn't open the message in Slack: " + err.Error())
+	}
+}
+
+func (p popup) actionsView() string {
+	width := p.input.Width()
+
+	var rows []string
+	for i, row := range p.actions {
+		style := lg.NewStyle().Foreground(p.theme.Text).Background(p.theme.Background)
+		if i == p.selected {
+			style = style.Foreground(p.theme.Selected).Bold(true)
+		}
+
+		label := controlLabel(row.element)
+		if row.option != nil {
+			label = "    ◦ "
+			if row.option.Text != nil {
+				label += strings.Join(strings.Fields(utils.Sanitize(row.option.Text.Text)), " ")
+			}
+		} else if row.element.URL != "" {
+			label += " ↗"
+		}
+		rows = append(rows, style.Width(width).Render(runewidth.Truncate(label, width, "…")))
+	}
+
+	mutedStyle := lg.NewStyle().Background(p.theme.Background).Foreground(p.theme.Muted).Width(width)
+
+	header := lg.NewStyle().Foreground(p.theme.Text).Background(p.theme.Background).Bold(true).Width(width).Render("Actions")
+	list := lg.JoinVertical(lg.Left, rows...)
+	status := mutedStyle.Render(utils.Sanitize(p.actionStatus))
+	help := lg.NewStyle().Background(p.theme.Background).Foreground(p.theme.Subtle).Width(width).Render("\n↑↓/Select  Enter/Open link  O/Slack  Esc/Close")
+
+	return lg.JoinVertical(lg.Left, header, list, status, help)
+}
@@ -0,0 +1,23 @@
+package channel
+
+import "testing"
+
+func TestIsWebURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://example.com/a?b=c":   true,
+		"HTTP://example.com":          true,
+		"file:///etc/passwd":          false,
+		"javascript:alert(1)":         false,
+		"ssh://host":                  false,
+		"-oProxyCommand=evil":         false,
+		"https:///no-host":            false,
+		"smb://server/share":          false,
+		"C:\\Windows\\System32\\calc": false,
+	}
+
+	for link, want := range tests {
+		if got := isWebURL(link); got != want {
+			t.Errorf("isWebURL(%q) = %v, want %v", link, got, want)
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package channel
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/utils"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	activityItemID   = "activity"
+	maxActivityItems = 100
+)
+
+type activityRow struct {
+	listRow
+	index int
+}
+
+func unreadActivity(cache *core.Cache) int {
+	count := 0
+	for _, item := range cache.Activity {
+		if !item.Read {
+			count++
+		}
+	}
+	return count
+}
+
+func sameActivity(first, second *core.ActivityItem) bool {
+	return first.Kind == second.Kind &&
+		first.ChannelID == second.ChannelID &&
+		first.Ts == second.Ts &&
+		first.User == second.User &&
+		first.Reaction == second.Reaction
+}
+
+func (a *app) addActivity(ws *workspace, items ...*core.ActivityItem) {
+	cache := a.cacheFor(ws)
+
+	added := false
+	for _, item := range items {
+		if slices.ContainsFunc(cache.Activity, func(existing *core.ActivityItem) bool { return sameActivity(existing, item) }) {
+			continue
+		}
+		cache.Activity = append(cache.Activity, item)
+		added = true
+	}
+	if !added {
+		return
+	}
+
+	slices.SortStableFunc(cache.Activity, func(first, second *core.ActivityItem) int {
+		return strings.Compare(second.EventTs, first.EventTs)
+	})
+	if len(cache.Activity) > maxActivityItems {
+		cache.Activity = cache.Activity[:maxActivityItems]
+	}
+
+	a.saveCache(cache)
+	a.refreshActivity(ws)
+}
+
+func (a *app) refreshActivity(ws *workspace) {
+	if ws != a.workspaces[a.activeWorkspace] {
+		return
+	}
+
+	a.rerenderSidebar()
+	if a.popup.isVisible && a.popup.popupType == PopupActivity {
+		a.updateActivityRows()
+	}
+}
+
+func (a *app) trackMessageActivity(ws *workspace, ev *api.MessageEvent) {
+	switch ev.SubType {
+	case "", "thread_broadcast", "file_share", "me_message":
+	default:
+		return
+	}
+	if ev.User == "" || ev.User == ws.user {
+		return
+	}
+
+	item := &core.ActivityItem{
+		ChannelID: ev.Channel,
+		Ts:        ev.Timestamp,
+		User:      ev.User,
+		Text:      ev.Text,
+		EventTs:   ev.Timestamp,
+	}
+	if ev.ThreadTimestamp != ev.Timestamp {
+		item.ThreadTs = ev.ThreadTimestamp
+	}
+
+	switch {
+	case utils.MentionsUser(ev.Text, ws.user):
+		item.Kind = "mention"
+	case item.ThreadTs != "" && ev.ParentUserID == ws.user:
+		item.Kind = "reply"
+	default:
+		return
+	}
+
+	item.Read = ws == a.workspaces[a.activeWorkspace] && !a.terminalBlurred && ev.Channel == a.CurrentChannel &&
+		(item.ThreadTs == "" || a.threadWindow.isOpen && a.threadWindow.parentTs == item.ThreadTs)
+
+	a.addActivity(ws, item)
+}
+
+func (a *app) trackReactionActivity(ws *workspace, ev *api.ReactionAddedEvent) {
+	if ev.ItemUser != ws.user || ev.User == ws.user || ev.Item.Channel == "" {
+		return
+	}
+
+	item := &core.ActivityItem{
+		Kind:      "reaction",
+		ChannelID: ev.Item.Channel,
+		Ts:        ev.Item.Timestamp,
+		User:      ev.User,
+		Reaction:  ev.Reaction,
+		EventTs:   ev.EventTimestamp,
+	}
+	if item.EventTs == "" {
+		item.EventTs = ev.Item.Timestamp
+	}
+
+	if mes, ok := ws.store.Message(ev.Item.Channel, ev.Item.Timestamp); ok {
+		item.Text = mes.Content
+		if mes.ThreadId != mes.Ts {
+			item.ThreadTs = mes.ThreadId
+		}
+	}
+
+	a.addActivity(ws, item)
+}
+
+func (a *app) untrackReactionActivity(ws *workspace, ev *api.ReactionRemovedEvent) {
+	cache := a.cacheFor(ws)
+
+	removed := &core.ActivityItem{Kind: "reaction", ChannelID: ev.Item.Channel, Ts: ev.Item.Timestamp, User: ev.User, Reaction: ev.Reaction}
+	i := slices.IndexFunc(cache.Activity, func(item *core.ActivityItem) bool { return sameActivity(item, removed) })
+	if i == -1 {
+		return
+	}
+
+	cache.Activity = slices.Delete(cache.Activity, i, i+1)
+	a.saveCache(cache)
+	a.refreshActivity(ws)
+}
+
+func (a *app) mergeFoundActivity(found []core.ActivityItem) {
+	items := make([]*core.ActivityItem, 0, len(found))
+	for _, item := range found {
+		conv, ok := a.Cache.Conversations[item.ChannelID]
+		item.Read = !ok || conv.LastRead >= item.Ts
+		items = append(items, &item)
+	}
+
+	a.addActivity(a.workspaces[a.activeWorkspace], items...)
+}
+
+func (a *app) openActivity(cmds *[]tea.Cmd) {
+	a.popup.popupType = PopupActivity
+	a.popup.input.SetWidth(70)
+	a.popup.input.Blur()
+	a.popup.isVisible = true
+	a.popup.selected = 0
+
+	a.updateActivityRows()
+
+	if !a.Offline {
+		*cmds = append(*cmds, api.SearchMentions(a.Client, a.Cache.Workspace, a.User))
+	}
+}
+
+func (a *app) activityKeybinds(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k", "ctrl+p":
+		if a.popup.selected > 0 {
+			a.popup.selected--
+		}
+	case "down", "j", "ctrl+n":
+		if a.popup.selected < len(a.popup.activity)-1 {
+			a.popup.selected++
+		}
+	case "a":
+		for _, item := range a.Cache.Activity {
+			item.Read = true
+		}
+		a.saveCache(a.Cache)
+		a.refreshActivity(a.workspaces[a.activeWorkspace])
+	case "enter":
+		if len(a.popup.activity) == 0 {
+			return a, nil
+		}
+		item := a.Cache.Activity[a.popup.activity[a.popup.selected].index]
+
+		if !item.Read {
+			item.Read = true
+			a.saveCache(a.Cache)
+			a.rerenderSidebar()
+		}
+		a.popup.isVisible = false
+
+		return a, func() tea.Msg {
+			return core.ChannelSelectedMsg{Id: item.ChannelID, Ts: item.Ts, ThreadTs: item.ThreadTs}
+		}
+	}
+	return a, nil
+}
+
+func (a *app) updateActivityRows() {
+	userName := func(userID string) string { return a.getUser(userID, false) }
+	channelName := func(channelID string) string { return a.getChannel(channelID, false) }
+
+	var rows []activityRow
+	for i, item := range a.Cache.Activity {
+		location := "#" + channelName(item.ChannelID)
+		if strings.HasPrefix(item.ChannelID, "D") {
+			location = "a DM"
+		}
+		if item.ThreadTs != "" {
+			location += " (thread)"
+		}
+
+		var title string
+		switch item.Kind {
+		case "mention":
+			title = userName(item.User) + " mentioned you in " + location
+		case "reply":
+			title = userName(item.User) + " replied to you in " + location
+		case "reaction":
+			title = userName(item.User) + " reacted " + a.emojiLabel(item.Reaction) + " to your message in " + location
+		}
+
+		text := utils.PlainMrkdwn(item.Text, userName, channelName, a.Cache.Emoji)
+
+		rows = append(rows, activityRow{
+			listRow: listRow{
+				title:     utils.Sanitize(title),
+				text:      strings.Join(strings.Fields(utils.Sanitize(text)), " "),
+				timestamp: formatShortTime(item.EventTs),
+				unread:    !item.Read,
+			},
+			index: i,
+		})
+	}
+
+	a.popup.activity = rows
+	a.popup.selected = min(a.popup.selected, max(0, len(rows)-1))
+}
+
+func (p popup) activityView() string {
+	rows := make([]listRow, len(p.activity))
+	for i, row := range p.activity {
+		rows[i] = row.listRow
+	}
+	return p.listView("Activity", rows,
+		"Nothing yet, mentions, replies and reactions to your messages show up here",
+		"↑↓/Select  Enter/Jump  A/Mark all read  Esc/Close")
+}
@@ -0,0 +1,144 @@
+package channel
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/utils"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+var hexColorPattern = regexp.MustCompile(`^#?[0-9a-fA-F]{6}$`)
+
+var attachmentColors = map[string]string{
+	"good":    "#2eb67d",
+	"warning": "#ecb22e",
+	"danger":  "#e01e5a",
+}
+
+func (a *app) attachmentColor(color string) lg.Color {
+	if named, ok := attachmentColors[color]; ok {
+		return lg.Color(named)
+	}
+	if hexColorPattern.MatchString(color) {
+		return lg.Color("#" + strings.TrimPrefix(color, "#"))
+	}
+	return a.theme.Border
+}
+
+func hasCardContent(attachment core.Attachment) bool {
+	return attachment.Pretext != "" || attachment.AuthorName != "" || attachment.Title != "" ||
+		attachment.Text != "" || len(attachment.Fields) > 0 || attachment.Footer != "" || showsFallback(attachment)
+}
+
+func showsFallback(attachment core.Attachment) bool {
+	return attachment.Fallback != "" && attachment.ImageURL == "" && attachment.ThumbURL == ""
+}
+
+func (a *app) attachmentCards(mes core.Message, width int) []string {
+	var cards []string
+	for _, attachment := range mes.Attachments {
+		if hasCardContent(attachment) && width > 4 {
+			cards = append(cards, a.attachmentCard(attachment, width))
+		}
+	}
+	return cards
+}
+
+func (a *app) attachmentCard(attachment core.Attachment, width int) string {
+	inner := width - 2
+	base := lg.NewStyle().Background(a.theme.Background).Width(inner)
+	oneLine := func(text string) string {
+		return runewidth.Truncate(strings.Join(strings.Fields(utils.Sanitize(text)), " "), inner, "…")
+	}
+
+	var lines []string
+	if attachment.AuthorName != "" {
+		lines = append(lines, base.Foreground(a.theme.Subtle).Bold(true).Render(oneLine(attachment.AuthorName)))
+	}
+
+	if attachment.Title != "" {
+		lines = append(lines, base.Foreground(a.theme.Secondary).Bold(true).Render(utils.Sanitize(attachment.Title)))
+
+		link := attachment.TitleLink
+		if link == "" {
+			link = attachment.FromURL
+		}
+		if link != "" {
+			lines = append(lines, base.Foreground(a.theme.Subtle).Render(oneLine(link)))
+		}
+	}
+
+	text := attachment.Text
+	if text == "" && len(lines) == 0 && len(attachment.Fields) == 0 && showsFallback(attachment) {
+		text = attachment.Fallback
+	}
+	if text != "" {
+		lines = append(lines, base.Foreground(a.theme.Text).Render(a.renderMrkdwn(utils.Sanitize(text), inner)))
+	}
+
+	fields := attachment.Fields
+	columnWidth := (inner - 1) / 2
+	for i := 0; i < len(fields); i++ {
+		if fields[i].Short && i+1 < len(fields) && fields[i+1].Short && columnWidth > 0 {
+			gap := lg.NewStyle().Background(a.theme.Background).Render(" ")
+			lines = append(lines, lg.JoinHorizontal(lg.Top,
+				a.attachmentField(fields[i], columnWidth),
+				gap,
+				a.attachmentField(fields[i+1], inner-columnWidth-1)))
+			i++
+			continue
+		}
+		lines = append(lines, a.attachmentField(fields[i], inner))
+	}
+
+	var footer []string
+	for _, part := range []string{attachment.ServiceName, attachment.Footer} {
+		if part != "" && !slices.Contains(footer, part) {
+			footer = append(footer, part)
+		}
+	}
+	if len(footer) > 0 {
+		lines = append(lines, base.Foreground(a.theme.Subtle).Render(oneLine(strings.Join(footer, " · "))))
+	}
+
+	var card string
+	if len(lines) > 0 {
+		content := lg.JoinVertical(lg.Left, lines...)
+		bar := lg.NewStyle().
+			Foreground(a.attachmentColor(attachment.Color)).
+			Background(a.theme.Background).
+			Render(strings.TrimSuffix(strings.Repeat("▌ \n", lg.Height(content)), "\n"))
+		card = lg.JoinHorizontal(lg.Top, bar, content)
+	}
+
+	if attachment.Pretext == "" {
+		return card
+	}
+
+	pretext := lg.NewStyle().
+		Background(a.theme.Background).
+		Foreground(a.theme.Text).
+		Width(width).
+		Render(a.renderMrkdwn(utils.Sanitize(attachment.Pretext), width))
+	if card == "" {
+		return pretext
+	}
+	return lg.JoinVertical(lg.Left, pretext, card)
+}
+
+func (a *app) attachmentField(field core.AttachmentField, width int) string {
+	style := lg.NewStyle().Background(a.theme.Background).Width(width)
+
+	var lines []string
+	if field.Title != "" {
+		lines = append(lines, style.Foreground(a.theme.Text).Bold(true).Render(utils.Sanitize(field.Title)))
+	}
+	if field.Value != "" {
+		lines = append(lines, style.Foreground(a.theme.Text).Render(a.renderMrkdwn(utils.Sanitize(field.Value), width)))
+	}
+	return lg.JoinVertical(lg.Left, lines...)
+}
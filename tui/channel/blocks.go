@@ -0,0 +1,371 @@
+package channel
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/images"
+	"github.com/Jan-Kur/HackCLI/utils"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+var (
+	markdownBoldRegex    = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	markdownLinkRegex    = regexp.MustCompile(`\[([^\]]+)\]\((\S+?)\)`)
+	markdownHeadingRegex = regexp.MustCompile(`(?m)^#{1,6} +(.*)$`)
+)
+
+var richTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func (a *app) renderBlocks(mes core.Message, width int) string {
+	fallback := func() string {
+		if mes.Content == "" {
+			return ""
+		}
+		return a.renderMrkdwn(utils.Sanitize(mes.Content), width)
+	}
+
+	var rendered []string
+	for _, block := range mes.Blocks {
+		text, ok := a.renderBlock(block, width)
+		if !ok {
+			return fallback()
+		}
+		if text != "" {
+			rendered = append(rendered, text)
+		}
+	}
+
+	if len(rendered) == 0 {
+		return fallback()
+	}
+	return lg.JoinVertical(lg.Left, rendered...)
+}
+
+func (a *app) renderBlock(block core.Block, width int) (string, bool) {
+	switch block.Type {
+	case "section":
+		return a.renderSection(block, width), true
+	case "header":
+		if block.Text == nil {
+			return "", true
+		}
+		return lg.NewStyle().
+			Foreground(a.theme.Text).
+			Background(a.theme.Background).
+			Bold(true).
+			Width(width).
+			Render(utils.ReplaceShortcodes(utils.Sanitize(block.Text.Text), a.Cache.Emoji)), true
+	case "divider":
+		return lg.NewStyle().
+			Foreground(a.theme.Border).
+			Background(a.theme.Background).
+			Render(strings.Repeat("─", width)), true
+	case "context":
+		return a.renderContext(block, width), true
+	case "rich_text":
+		return a.renderMrkdwn(utils.Sanitize(richTextMrkdwn(block.Elements)), width), true
+	case "markdown":
+		if block.Text == nil {
+			return "", true
+		}
+		return a.renderMrkdwn(utils.Sanitize(markdownToMrkdwn(block.Text.Text)), width), true
+	case "image":
+		return a.renderImageBlock(block, width), true
+	case "actions":
+		return a.renderControls(block.Elements, width), true
+	}
+
+	switch {
+	case block.Text != nil && block.Text.Text != "":
+		return a.renderBlockText(*block.Text, width), true
+	case block.Title != nil && block.Title.Text != "":
+		return a.renderBlockText(*block.Title, width), true
+	}
+	return "", false
+}
+
+func (a *app) renderBlockText(text core.BlockText, width int) string {
+	if text.Type == "plain_text" {
+		return lg.NewStyle().
+			Foreground(a.theme.Text).
+			Background(a.theme.Background).
+			Width(width).
+			Render(utils.ReplaceShortcodes(utils.Sanitize(text.Text), a.Cache.Emoji))
+	}
+	return a.renderMrkdwn(utils.Sanitize(text.Text), width)
+}
+
+func (a *app) renderSection(block core.Block, width int) string {
+	var parts []string
+	if block.Text != nil && block.Text.Text != "" {
+		parts = append(parts, a.renderBlockText(*block.Text, width))
+	}
+
+	columnWidth := (width - 1) / 2
+	for i := 0; i < len(block.Fields); i += 2 {
+		if i+1 == len(block.Fields) || columnWidth < 1 {
+			parts = append(parts, a.renderBlockText(block.Fields[i], width))
+			continue
+		}
+
+		gap := lg.NewStyle().Background(a.theme.Background).Render(" ")
+		left := lg.NewStyle().Background(a.theme.Background).Width(columnWidth).Render(a.renderBlockText(block.Fields[i], columnWidth))
+		right := lg.NewStyle().Background(a.theme.Background).Width(width - columnWidth - 1).Render(a.renderBlockText(block.Fields[i+1], width-columnWidth-1))
+		parts = append(parts, lg.JoinHorizontal(lg.Top, left, gap, right))
+	}
+
+	if block.Accessory != nil {
+		if controls := a.renderControls([]core.BlockElement{*block.Accessory}, width); controls != "" {
+			parts = append(parts, controls)
+		}
+	}
+	return lg.JoinVertical(lg.Left, parts...)
+}
+
+func (a *app) renderContext(block core.Block, width int) string {
+	userName := func(userID string) string { return a.getUser(userID, false) }
+	channelName := func(channelID string) string { return a.getChannel(channelID, false) }
+
+	var texts []string
+	for _, element := range block.Elements {
+		if element.Text != nil && element.Text.Text != "" {
+			texts = append(texts, utils.PlainMrkdwn(utils.Sanitize(element.Text.Text), userName, channelName, a.Cache.Emoji))
+		}
+	}
+	if len(texts) == 0 {
+		return ""
+	}
+
+	return lg.NewStyle().
+		Foreground(a.theme.Subtle).
+		Background(a.theme.Background).
+		Width(width).
+		Render(strings.Join(texts, "  "))
+}
+
+func (a *app) renderImageBlock(block core.Block, width int) string {
+	var parts []string
+	if block.Title != nil && block.Title.Text != "" {
+		parts = append(parts, lg.NewStyle().
+			Foreground(a.theme.Text).
+			Background(a.theme.Background).
+			Bold(true).
+			Width(width).
+			Render(utils.ReplaceShortcodes(utils.Sanitize(block.Title.Text), a.Cache.Emoji)))
+	}
+
+	var preview string
+	if a.imageProtocol != images.Off && block.ImageURL != "" {
+		preview = a.imagePreview(block.ImageURL, width)
+	}
+	if preview == "" && block.ImageURL != "" {
+		preview = a.styleLink(runewidth.Truncate(utils.Sanitize(block.ImageURL), width, "…"))
+	}
+	if preview != "" {
+		parts = append(parts, preview)
+	}
+	return lg.JoinVertical(lg.Left, parts...)
+}
+
+func (a *app) renderControls(elements []core.BlockElement, width int) string {
+	var lines []string
+	var line []string
+	lineWidth := 0
+
+	gap := lg.NewStyle().Background(a.theme.Background).Render(" ")
+	for _, element := range elements {
+		label := controlLabel(element)
+		if label == "" {
+			continue
+		}
+		control := a.controlStyle(element).Render(runewidth.Truncate(" "+label+" ", width, "…"))
+
+		if len(line) > 0 && lineWidth+1+lg.Width(control) > width {
+			lines = append(lines, strings.Join(line, gap))
+			line, lineWidth = nil, 0
+		}
+		if len(line) > 0 {
+			lineWidth++
+		}
+		line = append(line, control)
+		lineWidth += lg.Width(control)
+	}
+	if len(line) > 0 {
+		lines = append(lines, strings.Join(line, gap))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (a *app) controlStyle(element core.BlockElement) lg.Style {
+	style := lg.NewStyle().Foreground(a.theme.Text).Background(a.theme.Border)
+	if element.Style == nil {
+		return style
+	}
+
+	switch element.Style.Name {
+	case "primary":
+		return style.Foreground(a.theme.Background).Background(a.theme.Primary).Bold(true)
+	case "danger":
+		return style.Foreground(a.theme.Background).Background(lg.Color(attachmentColors["danger"])).Bold(true)
+	}
+	return style
+}
+
+func controlLabel(element core.BlockElement) string {
+	text := func(t *core.BlockText) string {
+		if t == nil {
+			return ""
+		}
+		return strings.Join(strings.Fields(utils.Sanitize(t.Text)), " ")
+	}
+
+	switch {
+	case element.Type == "button", element.Type == "workflow_button":
+		if label := text(element.Text); label != "" {
+			return label
+		}
+		return "Button"
+	case element.Type == "overflow":
+		return "⋯"
+	case strings.HasSuffix(element.Type, "_select"), element.Type == "datepicker", element.Type == "timepicker", element.Type == "datetimepicker":
+		label := text(element.Placeholder)
+		if element.InitialOption != nil && text(element.InitialOption.Text) != "" {
+			label = text(element.InitialOption.Text)
+		}
+		if label == "" {
+			label = "Select"
+		}
+		return label + " ▾"
+	}
+	return ""
+}
+
+func interactiveElements(mes core.Message) []core.BlockElement {
+	var elements []core.BlockElement
+	for _, block := range mes.Blocks {
+		if block.Type == "actions" {
+			for _, element := range block.Elements {
+				if controlLabel(element) != "" {
+					elements = append(elements, element)
+				}
+			}
+		}
+		if block.Accessory != nil && controlLabel(*block.Accessory) != "" {
+			elements = append(elements, *block.Accessory)
+		}
+	}
+	return elements
+}
+
+func richTextMrkdwn(elements []core.BlockElement) string {
+	var lines []string
+	for _, element := range elements {
+		switch element.Type {
+		case "rich_text_section":
+			lines = append(lines, strings.TrimSuffix(richTextInline(element.Elements), "\n"))
+		case "rich_text_list":
+			for i, item := range element.Elements {
+				marker := "•"
+				if element.Style != nil && element.Style.Name == "ordered" {
+					marker = strconv.Itoa(element.Offset+i+1) + "."
+				}
+				lines = append(lines, strings.Repeat("    ", element.Indent)+marker+" "+richTextInline(item.Elements))
+			}
+		case "rich_text_quote":
+			for _, line := range strings.Split(strings.TrimSuffix(richTextInline(element.Elements), "\n"), "\n") {
+				lines = append(lines, "> "+line)
+			}
+		case "rich_text_preformatted":
+			var code strings.Builder
+			for _, inner := range element.Elements {
+				code.WriteString(richTextPlain(inner))
+			}
+			lines = append(lines, "```\n"+strings.TrimSuffix(code.String(), "\n")+"\n```")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func richTextInline(elements []core.BlockElement) string {
+	var b strings.Builder
+	for _, element := range elements {
+		switch element.Type {
+		case "text":
+			b.WriteString(wrapStyle(richTextPlain(element), element.Style))
+		case "link":
+			label := richTextPlain(element)
+			if label == "" {
+				b.WriteString(wrapStyle("<"+element.URL+">", element.Style))
+			} else {
+				b.WriteString(wrapStyle("<"+element.URL+"|"+label+">", element.Style))
+			}
+		default:
+			b.WriteString(richTextPlain(element))
+		}
+	}
+	return b.String()
+}
+
+func richTextPlain(element core.BlockElement) string {
+	switch element.Type {
+	case "text":
+		if element.Text == nil {
+			return ""
+		}
+		return richTextEscaper.Replace(element.Text.Text)
+	case "link":
+		if element.Text == nil {
+			return richTextEscaper.Replace(element.URL)
+		}
+		return richTextEscaper.Replace(element.Text.Text)
+	case "user":
+		return "<@" + element.UserID + ">"
+	case "channel":
+		return "<#" + element.ChannelID + ">"
+	case "usergroup":
+		return "<!subteam^" + element.UsergroupID + ">"
+	case "broadcast":
+		return "<!" + element.Range + ">"
+	case "emoji":
+		return ":" + element.Name + ":"
+	case "date":
+		return richTextEscaper.Replace(element.Fallback)
+	}
+	return ""
+}
+
+func wrapStyle(text string, style *core.BlockStyle) string {
+	if style == nil || strings.TrimSpace(text) == "" {
+		return text
+	}
+
+	trimmed := strings.TrimLeft(text, " \n")
+	leading := text[:len(text)-len(trimmed)]
+	inner := strings.TrimRight(trimmed, " \n")
+	trailing := trimmed[len(inner):]
+
+	if style.Code {
+		inner = "`" + inner + "`"
+	}
+	if style.Strike {
+		inner = "~" + inner + "~"
+	}
+	if style.Italic {
+		inner = "_" + inner + "_"
+	}
+	if style.Bold {
+		inner = "*" + inner + "*"
+	}
+	return leading + inner + trailing
+}
+
+func markdownToMrkdwn(text string) string {
+	text = richTextEscaper.Replace(text)
+	text = markdownHeadingRegex.ReplaceAllString(text, "*$1*")
+	text = markdownBoldRegex.ReplaceAllString(text, "*$1*")
+	return markdownLinkRegex.ReplaceAllString(text, "<$2|$1>")
+}
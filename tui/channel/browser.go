@@ -0,0 +1,423 @@
+package channel
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/images"
+	"github.com/Jan-Kur/HackCLI/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/sahilm/fuzzy"
+)
+
+const maxBrowserRows = 8
+
+var channelIDRegex = regexp.MustCompile(`^[CG][A-Z0-9]{8,}$`)
+
+type browserState struct {
+	channels   []core.BrowsedChannel
+	rows       []core.BrowsedChannel
+	next       string
+	loaded     bool
+	loading    bool
+	err        string
+	offset     int
+	previewing bool
+	previews   map[string]*channelPreview
+	preview    chat
+}
+
+type channelPreview struct {
+	messages []core.Message
+	loading  bool
+	err      string
+}
+
+func (a *app) openBrowser() tea.Cmd {
+	width := min(max(60, a.width*7/10), a.width-6)
+
+	a.popup.popupType = PopupBrowser
+	a.popup.input.SetHeight(1)
+	a.popup.input.ShowLineNumbers = false
+	a.popup.input.Placeholder = "Filter public channels (or paste a channel ID)"
+	a.popup.input.SetWidth(width)
+	a.popup.input.Reset()
+	a.popup.isVisible = true
+	a.popup.input.Focus()
+	a.popup.selected = 0
+
+	browser := &a.popup.browser
+	browser.offset = 0
+	browser.previewing = false
+	browser.preview.chatWidth = width + 2
+	browser.preview.viewport.Width = width
+	browser.preview.viewport.Height = min(maxBrowserRows*2+1, max(5, a.height-12))
+	if browser.previews == nil {
+		browser.previews = make(map[string]*channelPreview)
+	}
+
+	a.updateBrowserRows()
+	if browser.loaded || browser.loading {
+		return nil
+	}
+
+	browser.loading = true
+	browser.err = ""
+	return api.ListPublicChannels(a.Client, a.Cache.Workspace, "")
+}
+
+func (a *app) browserKeybinds(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	browser := &a.popup.browser
+
+	switch msg.String() {
+	case "up", "ctrl+p":
+		if browser.previewing {
+			browser.preview.viewport.ScrollUp(1)
+		} else if a.popup.selected > 0 {
+			a.popup.selected--
+			a.scrollBrowser()
+		}
+	case "down", "ctrl+n":
+		if browser.previewing {
+			browser.preview.viewport.ScrollDown(1)
+		} else if a.popup.selected < len(browser.rows)-1 {
+			a.popup.selected++
+			a.scrollBrowser()
+		} else {
+			return a, a.loadMoreChannels()
+		}
+	case "tab":
+		if len(browser.rows) == 0 {
+			return a, nil
+		}
+		browser.previewing = !browser.previewing
+		if browser.previewing {
+			return a, a.previewChannel(browser.rows[a.popup.selected].ID)
+		}
+	case "enter", "alt+enter":
+		query := strings.TrimSpace(a.popup.input.Value())
+
+		var channel core.BrowsedChannel
+		switch {
+		case len(browser.rows) > 0:
+			channel = browser.rows[a.popup.selected]
+		case channelIDRegex.MatchString(query):
+			channel = core.BrowsedChannel{ID: query}
+		default:
+			return a, nil
+		}
+
+		a.popup.input.Reset()
+		a.popup.input.Blur()
+		a.popup.isVisible = false
+
+		if channel.IsMember {
+			return a, func() tea.Msg {
+				return core.ChannelSelectedMsg{Id: channel.ID}
+			}
+		}
+		go a.joinChannel(channel)
+	default:
+		a.popup.input, cmd = a.popup.input.Update(msg)
+		browser.previewing = false
+		a.popup.selected = 0
+		browser.offset = 0
+		a.updateBrowserRows()
+		return a, tea.Batch(cmd, a.fillBrowserRows())
+	}
+	return a, cmd
+}
+
+func (a *app) joinChannel(channel core.BrowsedChannel) {
+	_, _, _, err := a.Client.JoinConversation(channel.ID)
+	if err != nil {
+		a.showErrorPopup(fmt.Sprintf("Error joining channel: %v", err))
+		return
+	}
+
+	name := channel.Name
+	if name == "" {
+		name = a.getChannel(channel.ID, true)
+	}
+
+	a.MsgChan <- core.InsertChannelInSidebarMsg{ChannelName: name, ChannelID: channel.ID}
+	a.MsgChan <- core.ChannelSelectedMsg{Id: channel.ID}
+}
+
+func (a *app) loadMoreChannels() tea.Cmd {
+	browser := &a.popup.browser
+	if browser.loading || browser.next == "" {
+		return nil
+	}
+
+	browser.loading = true
+	return api.ListPublicChannels(a.Client, a.Cache.Workspace, browser.next)
+}
+
+func (a *app) fillBrowserRows() tea.Cmd {
+	if len(a.popup.browser.rows) >= maxBrowserRows {
+		return nil
+	}
+	return a.loadMoreChannels()
+}
+
+func (a *app) handleChannelsListed(msg core.ChannelsListedMsg) tea.Cmd {
+	browser := &a.popup.browser
+	if msg.Workspace != a.Cache.Workspace {
+		return nil
+	}
+
+	browser.loading = false
+	if msg.Err != nil {
+		browser.err = fmt.Sprintf("Couldn't list channels: %v", msg.Err)
+		return nil
+	}
+
+	if msg.Cursor == "" {
+		browser.channels = nil
+	}
+	for _, channel := range msg.Channels {
+		if !slices.ContainsFunc(browser.channels, func(c core.BrowsedChannel) bool { return c.ID == channel.ID }) {
+			browser.channels = append(browser.channels, channel)
+		}
+	}
+	browser.next = msg.Next
+	browser.loaded = true
+	browser.err = ""
+
+	if !a.popup.isVisible || a.popup.popupType != PopupBrowser {
+		return nil
+	}
+
+	var selectedID string
+	if a.popup.selected < len(browser.rows) {
+		selectedID = browser.rows[a.popup.selected].ID
+	}
+	a.updateBrowserRows()
+	if i := slices.IndexFunc(browser.rows, func(c core.BrowsedChannel) bool { return c.ID == selectedID }); i >= 0 {
+		a.popup.selected = i
+	}
+	a.scrollBrowser()
+
+	if strings.TrimSpace(a.popup.input.Value()) != "" {
+		return a.fillBrowserRows()
+	}
+	return nil
+}
+
+func (a *app) updateBrowserRows() {
+	browser := &a.popup.browser
+	query := strings.TrimLeft(strings.TrimSpace(a.popup.input.Value()), "#")
+
+	joined := make(map[string]bool)
+	for _, item := range a.sidebar.items {
+		joined[item.id] = true
+	}
+
+	channels := make([]core.BrowsedChannel, len(browser.channels))
+	for i, channel := range browser.channels {
+		channel.IsMember = channel.IsMember || joined[channel.ID]
+		channels[i] = channel
+	}
+
+	if query == "" {
+		slices.SortStableFunc(channels, func(first, second core.BrowsedChannel) int {
+			return second.NumMembers - first.NumMembers
+		})
+		browser.rows = channels
+	} else {
+		names := make([]string, len(channels))
+		for i, channel := range channels {
+			names[i] = channel.Name
+		}
+
+		var rows []core.BrowsedChannel
+		matched := make(map[string]bool)
+		for _, match := range fuzzy.Find(query, names) {
+			rows = append(rows, channels[match.Index])
+			matched[channels[match.Index].ID] = true
+		}
+
+		lower := strings.ToLower(query)
+		for _, channel := range channels {
+			if matched[channel.ID] {
+				continue
+			}
+			if channel.ID == query || strings.Contains(strings.ToLower(channel.Topic), lower) || strings.Contains(strings.ToLower(channel.Purpose), lower) {
+				rows = append(rows, channel)
+			}
+		}
+		browser.rows = rows
+	}
+
+	a.popup.selected = min(a.popup.selected, max(0, len(browser.rows)-1))
+}
+
+func (a *app) scrollBrowser() {
+	browser := &a.popup.browser
+	if a.popup.selected < browser.offset {
+		browser.offset = a.popup.selected
+	} else if a.popup.selected >= browser.offset+maxBrowserRows {
+		browser.offset = a.popup.selected - maxBrowserRows + 1
+	}
+}
+
+func (a *app) previewChannel(channelID string) tea.Cmd {
+	browser := &a.popup.browser
+	if preview, ok := browser.previews[channelID]; ok && (preview.loading || preview.err == "") {
+		a.renderChannelPreview()
+		return nil
+	}
+
+	browser.previews[channelID] = &channelPreview{loading: true}
+	a.renderChannelPreview()
+	return api.GetChannelPreview(a.Client, a.Cache.Workspace, channelID)
+}
+
+func (a *app) handleChannelPreview(msg core.ChannelPreviewMsg) {
+	browser := &a.popup.browser
+	if msg.Workspace != a.Cache.Workspace || browser.previews == nil {
+		return
+	}
+
+	preview := &channelPreview{messages: msg.Messages}
+	if msg.Err != nil {
+		preview.err = fmt.Sprintf("Couldn't load messages: %v", msg.Err)
+	}
+	browser.previews[msg.ChannelID] = preview
+
+	if a.popup.isVisible && a.popup.popupType == PopupBrowser && browser.previewing {
+		a.renderChannelPreview()
+	}
+}
+
+func (a *app) renderChannelPreview() {
+	browser := &a.popup.browser
+	if a.popup.selected >= len(browser.rows) {
+		return
+	}
+
+	preview := browser.previews[browser.rows[a.popup.selected].ID]
+	if preview == nil {
+		return
+	}
+
+	browser.preview.messages = preview.messages
+	browser.preview.selectedMessage = len(preview.messages) - 1
+	browser.preview.displayedMessages = make([]string, len(preview.messages))
+	for i, mes := range preview.messages {
+		browser.preview.displayedMessages[i] = a.formatMessage(mes, &browser.preview) + "\n"
+	}
+
+	browser.preview.viewport.SetContent(lg.JoinVertical(lg.Top, browser.preview.displayedMessages...))
+	browser.preview.viewport.GotoBottom()
+}
+
+func (p popup) browserView() string {
+	browser := p.browser
+	width := p.input.Width()
+
+	infoStyle := lg.NewStyle().Background(p.theme.Background).Foreground(p.theme.Subtle).Width(width)
+	mutedStyle := lg.NewStyle().Background(p.theme.Background).Foreground(p.theme.Muted).Width(width)
+
+	var status string
+	switch {
+	case browser.err != "":
+		status = browser.err
+	case !browser.loaded:
+		status = "Loading channels..."
+	case len(browser.rows) == 0 && channelIDRegex.MatchString(strings.TrimSpace(p.input.Value())):
+		status = "Press Enter to join " + strings.TrimSpace(p.input.Value())
+	case len(browser.rows) == 0:
+		status = "No matches"
+	default:
+		status = fmt.Sprintf("%d/%d channels", p.selected+1, len(browser.rows))
+	}
+	if browser.loading && browser.loaded {
+		status += " · loading more..."
+	}
+
+	var body string
+	if browser.previewing && p.selected < len(browser.rows) {
+		channel := browser.rows[p.selected]
+		preview := browser.previews[channel.ID]
+
+		var content string
+		switch {
+		case preview == nil || preview.loading:
+			content = mutedStyle.Render("Loading messages...")
+		case preview.err != "":
+			content = mutedStyle.Render(utils.Sanitize(preview.err))
+		case len(preview.messages) == 0:
+			content = mutedStyle.Render("No messages yet")
+		default:
+			content = images.ClipSixel(browser.preview.viewport.View())
+		}
+
+		header := lg.NewStyle().Background(p.theme.Background).Foreground(p.theme.Text).Bold(true).Width(width).
+			Render(runewidth.Truncate("#"+utils.Sanitize(channel.Name)+" · recent messages", width, "…"))
+		body = lg.JoinVertical(lg.Left, header, lg.NewStyle().
+			Width(width).
+			Height(browser.preview.viewport.Height).
+			Background(p.theme.Background).
+			Render(content))
+	} else {
+		var rows []string
+		end := min(len(browser.rows), browser.offset+maxBrowserRows)
+		for i := browser.offset; i < end; i++ {
+			rows = append(rows, p.browserRow(browser.rows[i], i == p.selected, width))
+		}
+		body = lg.JoinVertical(lg.Left, rows...)
+	}
+
+	help := infoStyle.Render("\n↑↓/Select  Tab/Preview  Enter/Join  Esc/Close")
+
+	return lg.JoinVertical(lg.Left, p.input.View(), infoStyle.Render(status), body, help)
+}
+
+func (p popup) browserRow(channel core.BrowsedChannel, selected bool, width int) string {
+	oneLine := func(text string) string {
+		return runewidth.Truncate(strings.Join(strings.Fields(utils.Sanitize(text)), " "), width-2, "…")
+	}
+
+	nameStyle := lg.NewStyle().Foreground(p.theme.Text).Background(p.theme.Background)
+	if selected {
+		nameStyle = nameStyle.Foreground(p.theme.Selected).Bold(true)
+	}
+	detailStyle := lg.NewStyle().Foreground(p.theme.Subtle).Background(p.theme.Background).Width(width)
+
+	details := fmt.Sprintf("%d members", channel.NumMembers)
+	if channel.NumMembers == 1 {
+		details = "1 member"
+	}
+	if channel.IsMember {
+		details = "joined · " + details
+	}
+
+	name := runewidth.Truncate("# "+utils.Sanitize(channel.Name), width-lg.Width(details)-1, "…")
+	gap := max(1, width-runewidth.StringWidth(name)-lg.Width(details))
+	lines := []string{nameStyle.Width(width).Render(name + strings.Repeat(" ", gap) + details)}
+
+	topic, purpose := oneLine(channel.Topic), oneLine(channel.Purpose)
+	switch {
+	case selected:
+		if topic != "" {
+			lines = append(lines, detailStyle.Render("  "+topic))
+		}
+		if purpose != "" && purpose != topic {
+			lines = append(lines, detailStyle.Render("  "+purpose))
+		}
+	case topic != "":
+		lines = append(lines, detailStyle.Render("  "+topic))
+	case purpose != "":
+		lines = append(lines, detailStyle.Render("  "+purpose))
+	}
+	return lg.JoinVertical(lg.Left, lines...)
+}
@@ -1,85 +1,170 @@
 package channel
 
 import (
+	"encoding/json"
 	"fmt"
 	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/Jan-Kur/HackCLI/api"
 	"github.com/Jan-Kur/HackCLI/core"
 	"github.com/slack-go/slack"
 )
 
-func (a *app) LoadConversations() {
-	var err error
-	var channels []core.Conversation
-	var dms []core.Conversation
-	var dmUsers map[string]*core.User
+const cacheSaveDelay = time.Second
 
-	if a.InitialLoading {
-		go func() {
-			channels, err = a.LoadChannels()
-			if err != nil {
-				a.showErrorPopup(fmt.Sprintf("Error loading channels: %v", err))
-			}
+type cacheSaver struct {
+	mu      sync.Mutex
+	dirty   map[string]*core.Cache
+	pending bool
+	writes  chan cacheWrite
+	done    chan struct{}
+}
 
-			dms, dmUsers, err = a.LoadDMs()
-			if err != nil {
-				a.showErrorPopup(fmt.Sprintf("Error loading dms: %v", err))
-			}
+type cacheWrite struct {
+	workspace string
+	data      []byte
+}
 
-			a.MsgChan <- core.FetchedCacheMsg{
-				Users:           dmUsers,
-				Conversations:   a.getConversationsMap(channels, dms),
-				SidebarChannels: channels,
-				SidebarDms:      dms,
-			}
-		}()
-	} else {
-		a.Cache = api.LoadCache()
-
-		for _, conv := range a.Cache.Conversations {
-			if conv.IsMember {
-				if strings.HasPrefix(conv.ID, "D") {
-					dms = append(dms, *conv)
-				} else {
-					channels = append(channels, *conv)
-				}
-			}
+func newCacheSaver() *cacheSaver {
+	saver := &cacheSaver{
+		dirty:  make(map[string]*core.Cache),
+		writes: make(chan cacheWrite, 16),
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(saver.done)
+		for write := range saver.writes {
+			api.WriteCache(write.workspace, write.data)
 		}
+	}()
+	return saver
+}
 
-		slices.SortFunc(dms, func(first, second core.Conversation) int {
-			return strings.Compare(first.User.Name, second.User.Name)
-		})
+func (a *app) saveCache(cache *core.Cache) {
+	a.saver.mu.Lock()
+	defer a.saver.mu.Unlock()
 
-		slices.SortFunc(channels, func(first, second core.Conversation) int {
-			return strings.Compare(first.Name, second.Name)
-		})
+	a.saver.dirty[cache.Workspace] = cache
+	if a.saver.pending {
+		return
+	}
+	a.saver.pending = true
 
-		a.initializeSidebar(channels, dms)
+	time.AfterFunc(cacheSaveDelay, func() {
+		a.MsgChan <- core.SaveCacheMsg{}
+	})
+}
 
-		go func() {
-			updatedChannels, err := a.LoadChannels()
-			if err != nil {
-				a.showErrorPopup(fmt.Sprintf("Error loading channels: %v", err))
-			}
+func (a *app) flushCaches() {
+	a.saver.mu.Lock()
+	dirty := a.saver.dirty
+	a.saver.dirty = make(map[string]*core.Cache)
+	a.saver.pending = false
+	a.saver.mu.Unlock()
 
-			updatedDms, updatedDmUsers, err := a.LoadDMs()
-			if err != nil {
-				a.showErrorPopup(fmt.Sprintf("Error loading dms: %v", err))
-			}
+	for workspace, cache := range dirty {
+		a.Mutex.RLock()
+		data, err := json.MarshalIndent(cache, "", "  ")
+		a.Mutex.RUnlock()
+
+		if err == nil {
+			a.saver.writes <- cacheWrite{workspace: workspace, data: data}
+		}
+	}
+}
+
+func (a *app) LoadConversations(ws *workspace) {
+	if ws.loading {
+		go a.fetchConversations(ws)
+		return
+	}
 
-			a.MsgChan <- core.FetchedCacheMsg{
-				Users:           updatedDmUsers,
-				Conversations:   a.getConversationsMap(updatedChannels, updatedDms),
-				SidebarChannels: updatedChannels,
-				SidebarDms:      updatedDms,
+	var channels []core.Conversation
+	var dms []core.Conversation
+	for _, conv := range ws.cache.Conversations {
+		if conv.IsMember {
+			if strings.HasPrefix(conv.ID, "D") {
+				dms = append(dms, *conv)
+			} else {
+				channels = append(channels, *conv)
 			}
-		}()
+		}
+	}
+
+	slices.SortFunc(dms, func(first, second core.Conversation) int {
+		return strings.Compare(first.User.Name, second.User.Name)
+	})
+
+	slices.SortFunc(channels, func(first, second core.Conversation) int {
+		return strings.Compare(first.Name, second.Name)
+	})
+
+	a.initializeSidebar(channels, dms)
+
+	if !a.Offline {
+		go a.fetchConversations(ws)
 	}
 }
 
-func (a *app) LoadChannels() ([]core.Conversation, error) {
+func (a *app) fetchConversations(ws *workspace) {
+	channels, staleChannels, err := a.LoadChannels(ws)
+	if err != nil {
+		a.showErrorPopup(fmt.Sprintf("Error loading channels: %v", err))
+	}
+
+	dms, dmUsers, staleDms, err := a.LoadDMs(ws)
+	if err != nil {
+		a.showErrorPopup(fmt.Sprintf("Error loading dms: %v", err))
+	}
+
+	a.MsgChan <- core.FetchedCacheMsg{
+		Workspace:       ws.config.Domain,
+		Users:           dmUsers,
+		Conversations:   a.getConversationsMap(channels, dms),
+		SidebarChannels: channels,
+		SidebarDms:      dms,
+	}
+
+	a.refreshUnreadState(ws, append(staleChannels, staleDms...))
+}
+
+func (a *app) refreshUnreadState(ws *workspace, conversationIDs []string) {
+	limiter := api.LimiterFor(ws.client)
+	api.ForEach(conversationIDs, loadingWorkers, func(id string) {
+		var info *slack.Channel
+		err := limiter.Call("conversations.info", func() error {
+			var err error
+			info, err = ws.client.GetConversationInfo(&slack.GetConversationInfoInput{
+				ChannelID: id,
+			})
+			return err
+		})
+		if err != nil {
+			return
+		}
+
+		latest := info.Latest
+		if latest == nil {
+			if latest, err = api.GetLatestMessage(ws.client, id); err != nil {
+				return
+			}
+		}
+
+		a.MsgChan <- core.ChannelReadMsg{
+			Workspace: ws.config.Domain,
+			ChannelID: id,
+			LatestTs:  latest.Timestamp,
+			LastRead:  info.LastRead,
+		}
+	})
+}
+
+func (a *app) LoadChannels(ws *workspace) ([]core.Conversation, []string, error) {
 	userChannelParams := &slack.GetConversationsForUserParameters{
 		Types:           []string{"public_channel", "private_channel"},
 		ExcludeArchived: true,
@@ -88,47 +173,37 @@ func (a *app) LoadChannels() ([]core.Conversation, error) {
 
 	userChannels, err := api.Paginate(func(cursor string) ([]slack.Channel, string, error) {
 		userChannelParams.Cursor = cursor
-		return a.Client.GetConversationsForUser(userChannelParams)
+		return ws.client.GetConversationsForUser(userChannelParams)
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var conversations []core.Conversation
+	var stale []string
+
 	for _, ch := range userChannels {
-		latest, err := api.GetLatestMessage(a.Client, ch.ID)
-		if err != nil {
-			continue
+		conv := a.cachedConversation(ws, ch.ID)
+		conv.Name = ch.Name
+		conv.IsMember = true
+
+		if ch.Latest != nil && ch.LastRead != "" {
+			conv.LatestMessage = ch.Latest.Timestamp
+			conv.LastRead = ch.LastRead
+		} else {
+			stale = append(stale, ch.ID)
 		}
-
-		channelInfo, err := a.Client.GetConversationInfo(&slack.GetConversationInfoInput{
-			ChannelID:     ch.ID,
-			IncludeLocale: true,
-		})
-
-		conversations = append(conversations, core.Conversation{
-			ID:            ch.ID,
-			Name:          ch.Name,
-			LastRead:      channelInfo.LastRead,
-			LatestMessage: latest.Timestamp,
-			IsMember:      true,
-		})
+		conversations = append(conversations, conv)
 	}
 
 	slices.SortFunc(conversations, func(a, b core.Conversation) int {
-		if a.Name < b.Name {
-			return -1
-		}
-		if a.Name > b.Name {
-			return 1
-		}
-		return 0
+		return strings.Compare(a.Name, b.Name)
 	})
 
-	return conversations, nil
+	return conversations, stale, nil
 }
 
-func (a *app) LoadDMs() ([]core.Conversation, map[string]*core.User, error) {
+func (a *app) LoadDMs(ws *workspace) ([]core.Conversation, map[string]*core.User, []string, error) {
 	dmParams := &slack.GetConversationsForUserParameters{
 		Types:           []string{"im"},
 		ExcludeArchived: true,
@@ -137,48 +212,79 @@ func (a *app) LoadDMs() ([]core.Conversation, map[string]*core.User, error) {
 
 	dms, err := api.Paginate(func(cursor string) ([]slack.Channel, string, error) {
 		dmParams.Cursor = cursor
-		return a.Client.GetConversationsForUser(dmParams)
+		return ws.client.GetConversationsForUser(dmParams)
 	})
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	dmsWithMessages, users, err := a.filterDMs(dms)
+	dmsWithMessages, users, stale := a.filterDMs(ws, dms)
 
 	slices.SortFunc(dmsWithMessages, func(first, second core.Conversation) int {
 		return strings.Compare(first.User.Name, second.User.Name)
 	})
 
-	return dmsWithMessages, users, nil
+	return dmsWithMessages, users, stale, nil
 }
 
-func (a *app) filterDMs(dms []slack.Channel) ([]core.Conversation, map[string]*core.User, error) {
+func (a *app) filterDMs(ws *workspace, dms []slack.Channel) ([]core.Conversation, map[string]*core.User, []string) {
 	var dmsWithMessages []core.Conversation
+	var stale []string
 	users := make(map[string]*core.User)
+	var unknown []slack.Channel
 
 	for _, dm := range dms {
-		latest, err := api.GetLatestMessage(a.Client, dm.ID)
-		if err != nil {
+		conv := a.cachedConversation(ws, dm.ID)
+		if !conv.IsMember || conv.LatestMessage == "" || conv.User.Name == "" || conv.User.Name == "..." {
+			unknown = append(unknown, dm)
 			continue
 		}
 
-		dmInfo, err := a.Client.GetConversationInfo(&slack.GetConversationInfoInput{
-			ChannelID:     dm.ID,
-			IncludeLocale: true,
+		dmsWithMessages = append(dmsWithMessages, conv)
+		stale = append(stale, dm.ID)
+	}
+
+	var mu sync.Mutex
+	var done atomic.Int32
+	limiter := api.LimiterFor(ws.client)
+
+	api.ForEach(unknown, loadingWorkers, func(dm slack.Channel) {
+		defer a.reportProgress(ws, "DMs", &done, len(unknown))
+
+		var dmInfo *slack.Channel
+		err := limiter.Call("conversations.info", func() error {
+			var err error
+			dmInfo, err = ws.client.GetConversationInfo(&slack.GetConversationInfoInput{
+				ChannelID:     dm.ID,
+				IncludeLocale: true,
+			})
+			return err
 		})
 		if err != nil {
-			return nil, nil, err
+			return
 		}
 
-		username := a.getUser(dm.User, true)
+		latest := dmInfo.Latest
+		if latest == nil {
+			if latest, err = api.GetLatestMessage(ws.client, dm.ID); err != nil {
+				return
+			}
+		}
 
-		users[dm.User] = &core.User{ID: dm.User, Name: username}
+		username := a.workspaceUser(ws, dm.User)
 
-		userPresence, err := a.Client.GetUserPresence(dm.User)
+		var userPresence *slack.UserPresence
+		err = limiter.Call("users.getPresence", func() error {
+			var err error
+			userPresence, err = ws.client.GetUserPresence(dm.User)
+			return err
+		})
 		if err != nil {
-			return nil, nil, err
+			return
 		}
 
+		mu.Lock()
+		users[dm.User] = &core.User{ID: dm.User, Name: username}
 		dmsWithMessages = append(dmsWithMessages, core.Conversation{
 			ID: dm.ID,
 			User: core.User{
@@ -190,8 +296,44 @@ func (a *app) filterDMs(dms []slack.Channel) ([]core.Conversation, map[string]*c
 			LatestMessage: latest.Timestamp,
 			IsMember:      true,
 		})
+		mu.Unlock()
+	})
+
+	return dmsWithMessages, users, stale
+}
+
+func (a *app) cachedConversation(ws *workspace, id string) core.Conversation {
+	a.Mutex.RLock()
+	defer a.Mutex.RUnlock()
+
+	if conv, ok := ws.cache.Conversations[id]; ok {
+		return *conv
+	}
+	return core.Conversation{ID: id}
+}
+
+func (a *app) workspaceUser(ws *workspace, userID string) string {
+	a.Mutex.RLock()
+	user, ok := ws.cache.Users[userID]
+	a.Mutex.RUnlock()
+	if ok && user.Name != "..." {
+		return user.Name
+	}
+
+	info, err := api.GetUserInfo(ws.client, userID)
+	if err != nil {
+		return "..."
+	}
+	return sanitize(api.DisplayName(info))
+}
+
+func (a *app) reportProgress(ws *workspace, stage string, done *atomic.Int32, total int) {
+	a.MsgChan <- core.LoadingProgressMsg{
+		Workspace: ws.config.Domain,
+		Stage:     stage,
+		Done:      int(done.Add(1)),
+		Total:     total,
 	}
-	return dmsWithMessages, users, nil
 }
 
 func (a *app) getConversationsMap(channels []core.Conversation, dms []core.Conversation) map[string]*core.Conversation {
@@ -0,0 +1,133 @@
+package channel
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/Jan-Kur/HackCLI/api/fake"
+	"github.com/Jan-Kur/HackCLI/core"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/slack-go/slack"
+)
+
+const (
+	benchmarkChannels = 300
+	benchmarkLatency  = 50 * time.Millisecond
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+type slowClient struct {
+	core.SlackClient
+	clock *fakeClock
+}
+
+func (c *slowClient) GetConversationsForUser(params *slack.GetConversationsForUserParameters) ([]slack.Channel, string, error) {
+	c.clock.Sleep(benchmarkLatency)
+	return c.SlackClient.GetConversationsForUser(params)
+}
+
+func (c *slowClient) GetConversationHistory(params *slack.GetConversationHistoryParameters) (*slack.GetConversationHistoryResponse, error) {
+	c.clock.Sleep(benchmarkLatency)
+	return c.SlackClient.GetConversationHistory(params)
+}
+
+func (c *slowClient) GetConversationInfo(input *slack.GetConversationInfoInput) (*slack.Channel, error) {
+	c.clock.Sleep(benchmarkLatency)
+	return c.SlackClient.GetConversationInfo(input)
+}
+
+func newBenchmarkWorkspace(clock *fakeClock) *workspace {
+	w := fake.NewWorkspace("U1", "me")
+	for i := range benchmarkChannels {
+		id := fmt.Sprintf("C%08d", i)
+		w.AddChannel(id, fmt.Sprintf("channel-%d", i), false, "U1")
+		w.AddMessage(id, "U1", "hello", "")
+	}
+
+	client := &slowClient{SlackClient: w, clock: clock}
+	api.UseLimiter(client, api.NewLimiterWithClock(clock))
+
+	return &workspace{
+		config: core.Workspace{Domain: "bench"},
+		client: client,
+		cache:  &core.Cache{Users: map[string]*core.User{}, Conversations: map[string]*core.Conversation{}, Workspace: "bench"},
+	}
+}
+
+// BenchmarkLoadChannels reports the simulated time until the sidebar can be
+// shown, with every request taking benchmarkLatency and going through the
+// workspace's rate limiter.
+func BenchmarkLoadChannels(b *testing.B) {
+	b.Run("per-channel", func(b *testing.B) {
+		var elapsed time.Duration
+		for range b.N {
+			clock := &fakeClock{now: time.Unix(0, 0)}
+			ws := newBenchmarkWorkspace(clock)
+			limiter := api.LimiterFor(ws.client)
+
+			channels, _, _ := ws.client.GetConversationsForUser(&slack.GetConversationsForUserParameters{Limit: benchmarkChannels})
+			for _, ch := range channels {
+				if _, err := api.GetLatestMessage(ws.client, ch.ID); err != nil {
+					b.Fatal(err)
+				}
+				err := limiter.Call("conversations.info", func() error {
+					_, err := ws.client.GetConversationInfo(&slack.GetConversationInfoInput{ChannelID: ch.ID})
+					return err
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			elapsed += clock.Now().Sub(time.Unix(0, 0))
+		}
+		b.ReportMetric(elapsed.Seconds()/float64(b.N), "sim-s/op")
+	})
+
+	b.Run("startup", func(b *testing.B) {
+		var elapsed time.Duration
+		for range b.N {
+			clock := &fakeClock{now: time.Unix(0, 0)}
+			ws := newBenchmarkWorkspace(clock)
+
+			msgChan := make(chan tea.Msg)
+			go func() {
+				for range msgChan {
+				}
+			}()
+
+			a := &app{App: core.App{MsgChan: msgChan}}
+			channels, stale, err := a.LoadChannels(ws)
+			if err == nil {
+				_, _, _, err = a.LoadDMs(ws)
+			}
+			close(msgChan)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if len(channels) != benchmarkChannels || len(stale) != benchmarkChannels {
+				b.Fatalf("loaded %d channels with %d to refresh, want %d", len(channels), len(stale), benchmarkChannels)
+			}
+			elapsed += clock.Now().Sub(time.Unix(0, 0))
+		}
+		b.ReportMetric(elapsed.Seconds()/float64(b.N), "sim-s/op")
+	})
+}
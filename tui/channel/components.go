@@ -1,10 +1,13 @@
 package channel
 
 import (
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/Jan-Kur/HackCLI/core"
 	"github.com/Jan-Kur/HackCLI/tui/styles"
+	"github.com/Jan-Kur/HackCLI/utils"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -14,7 +17,8 @@ import (
 )
 
 const (
-	itemHeight = 3
+	itemHeight      = 3
+	visibleListRows = 8
 )
 
 type sidebar struct {
@@ -32,6 +36,10 @@ type chat struct {
 	displayedMessages     []string
 	selectedMessage       int
 	chatWidth, chatHeight int
+	hasMore               bool
+	hasNewer              bool
+	loadingOlder          bool
+	loadingNewer          bool
 }
 
 type sidebarItem struct {
@@ -41,6 +49,17 @@ type sidebarItem struct {
 	userID   string
 }
 
+func (item sidebarItem) isView() bool {
+	return item.id == threadsItemID || item.id == activityItemID
+}
+
+type listRow struct {
+	title     string
+	text      string
+	timestamp string
+	unread    bool
+}
+
 func initializePopup(theme styles.Theme) textarea.Model {
 	t := textarea.New()
 
@@ -83,27 +102,33 @@ func initializeInput(theme styles.Theme) textarea.Model {
 }
 
 func (a *app) initializeSidebar(channels []core.Conversation, dms []core.Conversation) {
+	s, channelID := a.newSidebar(channels, dms, a.CurrentChannel)
+	s.width, s.height = a.sidebar.width, a.sidebar.height
+	a.sidebar, a.CurrentChannel = s, channelID
+}
+
+func (a *app) newSidebar(channels []core.Conversation, dms []core.Conversation, initialChannel string) (sidebar, string) {
 	items := a.buildSidebar(channels, dms)
 
-	a.CurrentChannel = strings.TrimPrefix(a.CurrentChannel, "#")
+	initialChannel = strings.TrimPrefix(initialChannel, "#")
 
 	var initialChannelID string
 	for _, ch := range channels {
-		if ch.Name == a.CurrentChannel {
+		if ch.Name == initialChannel || ch.ID == initialChannel {
 			initialChannelID = ch.ID
 			break
 		}
 	}
 
 	for _, dm := range dms {
-		if dm.User.Name == a.CurrentChannel {
+		if dm.User.Name == initialChannel || dm.ID == initialChannel {
 			initialChannelID = dm.ID
 		}
 	}
 
 	if initialChannelID == "" {
-		if len(items) > 0 {
-			initialChannelID = items[1].id
+		if i := slices.IndexFunc(items, func(item sidebarItem) bool { return !item.isHeader && !item.isView() }); i != -1 {
+			initialChannelID = items[i].id
 		} else {
 			a.showErrorPopup("No channels detected")
 		}
@@ -119,10 +144,7 @@ func (a *app) initializeSidebar(channels []core.Conversation, dms []core.Convers
 		}
 	}
 
-	a.sidebar.items = items
-	a.sidebar.selectedItem = selectedItem
-	a.sidebar.openChannel = selectedItem
-	a.CurrentChannel = initialChannelID
+	return sidebar{items: items, selectedItem: selectedItem, openChannel: selectedItem}, initialChannelID
 }
 
 func (s sidebar) Update(msg tea.Msg) (sidebar, tea.Cmd) {
@@ -168,7 +190,7 @@ func (s sidebar) Update(msg tea.Msg) (sidebar, tea.Cmd) {
 	return s, nil
 }
 
-func (s sidebar) View(theme styles.Theme, cache *core.Cache) string {
+func (s sidebar) View(theme styles.Theme, cache *core.Cache, muted []string) string {
 	const (
 		borderX      = 2
 		IconBoxWidth = 3
@@ -209,7 +231,19 @@ func (s sidebar) View(theme styles.Theme, cache *core.Cache) string {
 				items = append(items, headerStyle.Render(truncated))
 			}
 		} else {
-			unread := cache.Conversations[item.id].LastRead < cache.Conversations[item.id].LatestMessage
+			conv, ok := cache.Conversations[item.id]
+			if !ok {
+				conv = &core.Conversation{ID: item.id}
+			}
+			switch item.id {
+			case threadsItemID:
+				conv = &core.Conversation{ID: item.id, UnreadCount: unreadThreads(cache)}
+			case activityItemID:
+				conv = &core.Conversation{ID: item.id, MentionCount: unreadActivity(cache)}
+			}
+			isDM := strings.HasPrefix(item.id, "D")
+			isMuted := slices.Contains(muted, item.id)
+			unread := (conv.LastRead < conv.LatestMessage || item.isView() && conv.UnreadCount+conv.MentionCount > 0) && !isMuted
 
 			channelStyle := lg.NewStyle().
 				Border(lg.RoundedBorder(), true, true, true, false).
@@ -227,18 +261,25 @@ func (s sidebar) View(theme styles.Theme, cache *core.Cache) string {
 				BorderBackground(theme.Background)
 
 			icon := "#"
-			if strings.HasPrefix(item.id, "D") {
-				if cache.Conversations[item.id].UserPresence == "active" {
+			switch {
+			case item.id == threadsItemID:
+				icon = "≡"
+			case item.id == activityItemID:
+				icon = "@"
+			case isDM:
+				if conv.UserPresence == "active" {
 					icon = "⬤"
 					iconBox = iconBox.Foreground(styles.Green)
 				} else {
 					icon = "◯"
 					iconBox = iconBox.Foreground(styles.Gray)
 				}
+			case isMuted:
+				icon = "∅"
 			}
 
 			if unread {
-				if icon == "#" {
+				if !isDM {
 					iconBox = iconBox.Foreground(theme.Text).Bold(true)
 				}
 				channelStyle = channelStyle.Foreground(theme.Text).Bold(true)
@@ -249,22 +290,35 @@ func (s sidebar) View(theme styles.Theme, cache *core.Cache) string {
 				iconBox = iconBox.BorderForeground(theme.Selected)
 			}
 			if s.openChannel == absoluteIndex {
-				if icon == "#" {
+				if !isDM {
 					iconBox = iconBox.Foreground(theme.Selected)
 				}
 				channelStyle = channelStyle.Foreground(theme.Selected)
 			}
 
-			availableWidth := s.width - borderX - IconBoxWidth - 1
-			var styledChannel string
+			badge := unreadBadge(theme, conv)
+			badgeWidth := 0
+			if badge != "" {
+				badgeWidth = lg.Width(badge) + 1
+			}
 
-			if runewidth.StringWidth(item.title) <= availableWidth {
-				styledChannel = channelStyle.Render(item.title)
-			} else {
-				truncated := runewidth.Truncate(item.title, availableWidth-1, "")
-				styledChannel = channelStyle.Render(truncated + "…")
+			availableWidth := s.width - borderX - IconBoxWidth - 1 - badgeWidth
+			title := utils.Sanitize(item.title)
+
+			if runewidth.StringWidth(title) > availableWidth {
+				truncated := runewidth.Truncate(title, availableWidth-1, "")
+				title = truncated + "…"
 			}
 
+			if badge != "" {
+				titleStyle := lg.NewStyle().
+					Foreground(channelStyle.GetForeground()).
+					Background(theme.Background).
+					Bold(channelStyle.GetBold())
+				title = titleStyle.Render(title+" ") + badge
+			}
+			styledChannel := channelStyle.Render(title)
+
 			finalItem := lg.NewStyle().
 				Width(s.width).
 				Background(theme.Background).
@@ -278,6 +332,27 @@ func (s sidebar) View(theme styles.Theme, cache *core.Cache) string {
 	return container
 }
 
+func unreadBadge(theme styles.Theme, conv *core.Conversation) string {
+	count := conv.UnreadCount
+	badgeStyle := lg.NewStyle().Foreground(theme.Text).Background(theme.Muted)
+
+	if conv.MentionCount > 0 {
+		count = conv.MentionCount
+		badgeStyle = lg.NewStyle().Foreground(theme.Background).Background(theme.Selected).Bold(true)
+	}
+
+	if count == 0 {
+		return ""
+	}
+
+	label := strconv.Itoa(count)
+	if count > 99 {
+		label = "99+"
+	}
+
+	return badgeStyle.Render(" " + label + " ")
+}
+
 func (s *sidebar) SetWidth(w int) {
 	s.width = w
 }
@@ -301,6 +376,34 @@ func (s *sidebar) SetHeight(h int) {
 	}
 }
 
+func (s *sidebar) focusItem(id string) {
+	s.openChannel = -1
+
+	for i, item := range s.items {
+		if item.id == id && !item.isHeader {
+			s.openChannel = i
+			s.selectedItem = i
+			break
+		}
+	}
+
+	if s.openChannel == -1 {
+		return
+	}
+
+	if s.selectedItem < s.scrollOffset {
+		s.scrollOffset = s.selectedItem
+	}
+
+	for {
+		_, end := s.visibleRange()
+		if end == len(s.items) || s.selectedItem < end {
+			break
+		}
+		s.scrollOffset++
+	}
+}
+
 func (s *sidebar) nextItem(currentIndex int, direction int) int {
 	nextIndex := currentIndex + direction
 
@@ -315,7 +418,7 @@ func (s *sidebar) nextItem(currentIndex int, direction int) int {
 }
 
 func (a *app) rerenderSidebar() {
-	a.sidebar.View(a.theme, a.Cache)
+	a.sidebar.View(a.theme, a.Cache, a.Config.Notifications.Muted)
 }
 
 func (s *sidebar) visibleRange() (start, end int) {
@@ -365,13 +468,77 @@ func (p popup) View() string {
 	var body string
 
 	switch p.popupType {
-	case PopupReaction, PopupEdit, PopupJoinChannel:
+	case PopupReaction:
+		body = p.emojiPickerView()
+	case PopupEdit:
 		help := lg.NewStyle().Background(p.theme.Background).Foreground(p.theme.Subtle).Width(p.input.Width()).Render("\nAlt+Enter/Add  Esc/Cancel")
 		body = lg.JoinVertical(lg.Left, p.input.View(), help)
+	case PopupSwitcher:
+		body = p.switcherView()
+	case PopupSearch:
+		body = p.searchView()
+	case PopupThreads:
+		body = p.threadsView()
+	case PopupActivity:
+		body = p.activityView()
+	case PopupUpload:
+		body = p.uploadView()
+	case PopupFiles:
+		body = p.filesView()
+	case PopupActions:
+		body = p.actionsView()
+	case PopupBrowser:
+		body = p.browserView()
 	}
 	return box.Render(body)
 }
 
+func (p popup) listView(header string, rows []listRow, empty, help string) string {
+	width := p.input.Width()
+
+	var lines []string
+
+	start := max(0, p.selected-visibleListRows+1)
+	end := min(len(rows), start+visibleListRows)
+	for i := start; i < end; i++ {
+		row := rows[i]
+
+		titleStyle := lg.NewStyle().Foreground(p.theme.Subtle).Background(p.theme.Background)
+		textStyle := lg.NewStyle().Foreground(p.theme.Muted).Background(p.theme.Background)
+		if row.unread {
+			titleStyle = titleStyle.Foreground(p.theme.Text).Bold(true)
+			textStyle = textStyle.Foreground(p.theme.Text)
+		}
+		if i == p.selected {
+			titleStyle = titleStyle.Foreground(p.theme.Selected).Bold(true)
+		}
+
+		marker := "  "
+		if row.unread {
+			marker = "● "
+		}
+
+		title := runewidth.Truncate(marker+row.title, width-lg.Width(row.timestamp)-1, "…")
+		gap := max(1, width-runewidth.StringWidth(title)-lg.Width(row.timestamp))
+		text := runewidth.Truncate("  "+row.text, width, "…")
+
+		lines = append(lines,
+			titleStyle.Width(width).Render(title+strings.Repeat(" ", gap)+row.timestamp),
+			textStyle.Width(width).Render(text),
+		)
+	}
+
+	if len(lines) == 0 {
+		lines = append(lines, lg.NewStyle().Foreground(p.theme.Muted).Background(p.theme.Background).Width(width).Render(empty))
+	}
+
+	headerLine := lg.NewStyle().Foreground(p.theme.Text).Background(p.theme.Background).Bold(true).Width(width).Render(header)
+	list := lg.JoinVertical(lg.Left, lines...)
+	helpLine := lg.NewStyle().Background(p.theme.Background).Foreground(p.theme.Subtle).Width(width).Render("\n" + help)
+
+	return lg.JoinVertical(lg.Left, headerLine, list, helpLine)
+}
+
 func (e errorPopup) Init() tea.Cmd                           { return nil }
 func (e errorPopup) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return e, nil }
 func (e errorPopup) View() string {
@@ -382,7 +549,7 @@ func (e errorPopup) View() string {
 		BorderBackground(e.theme.Background).
 		Padding(0, 1)
 
-	wrapped := wordwrap.String(e.err, 30)
+	wrapped := wordwrap.String(utils.Sanitize(e.err), 30)
 
 	return box.Render(lg.NewStyle().Background(e.theme.Background).Foreground(styles.Pink).Render(wrapped))
 }
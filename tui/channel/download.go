@@ -0,0 +1,157 @@
+package channel
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+func (a *app) openFiles(mes core.Message) {
+	if len(mes.Files) == 0 {
+		return
+	}
+
+	a.popup.popupType = PopupFiles
+	a.popup.targetMes = mes
+	a.popup.input.SetWidth(60)
+	a.popup.input.Blur()
+	a.popup.isVisible = true
+	a.popup.selected = 0
+	a.popup.fileStatus = ""
+}
+
+func (a *app) filesKeybinds(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k", "ctrl+p":
+		if a.popup.selected > 0 {
+			a.popup.selected--
+		}
+	case "down", "j", "ctrl+n":
+		if a.popup.selected < len(a.popup.targetMes.Files)-1 {
+			a.popup.selected++
+		}
+	case "enter", "s":
+		a.downloadSelected(false)
+	case "o":
+		a.downloadSelected(true)
+	}
+	return a, nil
+}
+
+func (a *app) downloadSelected(open bool) {
+	if a.Offline {
+		a.popup.fileStatus = "Can't download files in offline mode"
+		return
+	}
+
+	dir, err := api.DownloadsDir(a.Config)
+	if err != nil {
+		a.popup.fileStatus = err.Error()
+		return
+	}
+
+	file := a.popup.targetMes.Files[a.popup.selected]
+	a.popup.fileStatus = "Downloading " + displayFileName(file) + "…"
+
+	go a.downloadFile(a.workspaces[a.activeWorkspace].config, file, dir, open)
+}
+
+func (a *app) downloadFile(workspace core.Workspace, file core.File, dir string, open bool) {
+	name := displayFileName(file)
+	a.MsgChan <- core.DownloadProgressMsg{Name: name}
+
+	path, err := api.DownloadFile(workspace, file, dir, func(sent, total int64) {
+		a.MsgChan <- core.DownloadProgressMsg{Name: name, Sent: sent, Total: total}
+	})
+	if err == nil && open {
+		err = utils.Open(path)
+	}
+	a.MsgChan <- core.DownloadFinishedMsg{Name: name, Path: path, Open: open, Err: err}
+}
+
+func (a *app) downloadFinished(msg core.DownloadFinishedMsg) {
+	if a.downloading.Name == msg.Name {
+		a.downloading = core.DownloadProgressMsg{}
+	}
+
+	status := "Saved to " + msg.Path
+	if msg.Err != nil {
+		status = fmt.Sprintf("Error downloading %v: %v", msg.Name, msg.Err)
+	} else if msg.Open {
+		status = "Opened " + msg.Path
+	}
+
+	if a.popup.isVisible && a.popup.popupType == PopupFiles {
+		a.popup.fileStatus = status
+	} else if msg.Err != nil {
+		go a.showErrorPopup(status)
+	}
+}
+
+func (a *app) downloadIndicator() string {
+	if a.downloading.Name == "" {
+		return ""
+	}
+
+	name := runewidth.Truncate(a.downloading.Name, 30, "…")
+	if a.downloading.Total == 0 {
+		return " · downloading " + name
+	}
+	return fmt.Sprintf(" · downloading %v %d%%", name, a.downloading.Sent*100/a.downloading.Total)
+}
+
+func displayFileName(file core.File) string {
+	name := strings.Join(strings.Fields(utils.Sanitize(file.Name)), " ")
+	if name == "" {
+		return file.ID
+	}
+	return name
+}
+
+func formatFileSize(size int) string {
+	switch {
+	case size >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(size)/(1<<30))
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", size)
+}
+
+func (p popup) filesView() string {
+	width := p.input.Width()
+
+	var rows []string
+	for i, file := range p.targetMes.Files {
+		style := lg.NewStyle().Foreground(p.theme.Text).Background(p.theme.Background)
+		if i == p.selected {
+			style = style.Foreground(p.theme.Selected).Bold(true)
+		}
+
+		details := formatFileSize(file.Size)
+		if file.Mimetype != "" {
+			details = utils.Sanitize(file.Mimetype) + " · " + details
+		}
+
+		name := runewidth.Truncate(displayFileName(file), width-lg.Width(details)-1, "…")
+		gap := max(1, width-runewidth.StringWidth(name)-lg.Width(details))
+		rows = append(rows, style.Width(width).Render(name+strings.Repeat(" ", gap)+details))
+	}
+
+	mutedStyle := lg.NewStyle().Background(p.theme.Background).Foreground(p.theme.Muted).Width(width)
+
+	header := lg.NewStyle().Foreground(p.theme.Text).Background(p.theme.Background).Bold(true).Width(width).Render("Files")
+	list := lg.JoinVertical(lg.Left, rows...)
+	status := mutedStyle.Render(runewidth.Truncate(utils.Sanitize(p.fileStatus), width, "…"))
+	help := lg.NewStyle().Background(p.theme.Background).Foreground(p.theme.Subtle).Width(width).Render("\n↑↓/Select  Enter/Save  O/Open  Esc/Close")
+
+	return lg.JoinVertical(lg.Left, header, list, status, help)
+}
@@ -0,0 +1,252 @@
+package channel
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/slack-go/slack"
+)
+
+const (
+	emojiCellWidth   = 5
+	emojiGridRows    = 6
+	frequentEmojiMax = 16
+)
+
+func (a *app) openEmojiPicker(mes core.Message) {
+	a.popup.popupType = PopupReaction
+	a.popup.targetMes = mes
+	a.popup.customEmoji = a.Cache.Emoji
+	a.popup.input.SetHeight(1)
+	a.popup.input.ShowLineNumbers = false
+	a.popup.input.Placeholder = "Search emoji..."
+	a.popup.input.SetWidth(50)
+	a.popup.input.Reset()
+	a.popup.isVisible = true
+	a.popup.input.Focus()
+
+	a.updateEmojiResults()
+}
+
+func (a *app) emojiPickerKeybinds(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	columns := a.popup.emojiColumns()
+
+	switch msg.String() {
+	case "left":
+		if a.popup.selected > 0 {
+			a.popup.selected--
+		}
+	case "right":
+		if a.popup.selected < len(a.popup.emojis)-1 {
+			a.popup.selected++
+		}
+	case "up":
+		if a.popup.selected-columns >= 0 {
+			a.popup.selected -= columns
+		}
+	case "down":
+		if a.popup.selected+columns < len(a.popup.emojis) {
+			a.popup.selected += columns
+		} else if len(a.popup.emojis) > 0 {
+			a.popup.selected = len(a.popup.emojis) - 1
+		}
+	case "enter", "alt+enter":
+		name := strings.Trim(strings.TrimSpace(a.popup.input.Value()), ":")
+		if len(a.popup.emojis) > 0 {
+			name = a.popup.emojis[a.popup.selected]
+		}
+		if name == "" {
+			return a, nil
+		}
+
+		a.toggleReaction(a.popup.targetMes, name)
+
+		a.popup.input.Reset()
+		a.popup.input.Blur()
+		a.popup.isVisible = false
+	default:
+		a.popup.input, cmd = a.popup.input.Update(msg)
+		a.updateEmojiResults()
+	}
+	return a, cmd
+}
+
+func (a *app) toggleReaction(mes core.Message, name string) {
+	channelID := a.CurrentChannel
+	client := a.Client
+
+	if users, ok := mes.Reactions[name]; ok && slices.Contains(users, a.User) {
+		go func() {
+			err := client.RemoveReaction(name, slack.ItemRef{
+				Channel:   channelID,
+				Timestamp: mes.Ts,
+			})
+			if err == nil {
+				a.MsgChan <- core.ReactionScrollMsg{Added: false}
+			}
+		}()
+		return
+	}
+
+	if a.Cache.EmojiUsage == nil {
+		a.Cache.EmojiUsage = make(map[string]int)
+	}
+	a.Cache.EmojiUsage[name]++
+	a.saveCache(a.Cache)
+
+	go func() {
+		err := client.AddReaction(name, slack.ItemRef{
+			Channel:   channelID,
+			Timestamp: mes.Ts,
+		})
+		if err == nil {
+			a.MsgChan <- core.ReactionScrollMsg{Added: true}
+		}
+	}()
+}
+
+func (a *app) updateEmojiResults() {
+	query := strings.ToLower(strings.Trim(strings.TrimSpace(a.popup.input.Value()), ":"))
+	names := utils.EmojiNames(a.Cache.Emoji)
+
+	var results []string
+	if query == "" {
+		results = append(results, a.frequentEmoji()...)
+		results = append(results, utils.PopularEmoji...)
+		results = append(results, names...)
+	} else {
+		var prefixed, contained []string
+		for _, name := range names {
+			if strings.HasPrefix(name, query) {
+				prefixed = append(prefixed, name)
+			} else if strings.Contains(name, query) {
+				contained = append(contained, name)
+			}
+		}
+
+		byUsage := func(first, second string) int {
+			return a.Cache.EmojiUsage[second] - a.Cache.EmojiUsage[first]
+		}
+		slices.SortStableFunc(prefixed, byUsage)
+		slices.SortStableFunc(contained, byUsage)
+
+		results = append(prefixed, contained...)
+	}
+
+	seen := make(map[string]bool)
+	a.popup.emojis = slices.DeleteFunc(results, func(name string) bool {
+		if seen[name] {
+			return true
+		}
+		seen[name] = true
+		return false
+	})
+	a.popup.selected = 0
+}
+
+func (a *app) frequentEmoji() []string {
+	var names []string
+	for name := range a.Cache.EmojiUsage {
+		names = append(names, name)
+	}
+
+	slices.SortFunc(names, func(first, second string) int {
+		if diff := a.Cache.EmojiUsage[second] - a.Cache.EmojiUsage[first]; diff != 0 {
+			return diff
+		}
+		return strings.Compare(first, second)
+	})
+
+	if len(names) > frequentEmojiMax {
+		names = names[:frequentEmojiMax]
+	}
+	return names
+}
+
+func (a *app) loadCustomEmoji(ws *workspace) {
+	var emoji map[string]string
+	var err error
+
+	api.WithRetry(func() error {
+		emoji, err = ws.client.GetEmoji()
+		return err
+	})
+	if err != nil {
+		return
+	}
+
+	a.MsgChan <- core.EmojiLoadedMsg{Workspace: ws.config.Domain, Emoji: emoji}
+}
+
+func (a *app) emojiLabel(name string) string {
+	if emoji, ok := utils.Emoji(name, a.Cache.Emoji); ok {
+		return emoji
+	}
+	return ":" + utils.Sanitize(name) + ":"
+}
+
+func (p popup) emojiColumns() int {
+	return max(1, p.input.Width()/emojiCellWidth)
+}
+
+func (p popup) emojiPickerView() string {
+	columns := p.emojiColumns()
+	width := p.input.Width()
+
+	cellStyle := lg.NewStyle().
+		Width(emojiCellWidth).
+		Align(lg.Center).
+		Background(p.theme.Background).
+		Foreground(p.theme.Text)
+	selectedStyle := cellStyle.Background(p.theme.Selected)
+
+	selectedRow := p.selected / columns
+	startRow := (selectedRow / emojiGridRows) * emojiGridRows
+
+	var rows []string
+	for row := startRow; row < startRow+emojiGridRows; row++ {
+		var cells []string
+		for column := range columns {
+			index := row*columns + column
+			if index >= len(p.emojis) {
+				break
+			}
+
+			glyph, ok := utils.Emoji(p.emojis[index], p.customEmoji)
+			if !ok {
+				glyph = "◇"
+			}
+
+			if index == p.selected {
+				cells = append(cells, selectedStyle.Render(glyph))
+			} else {
+				cells = append(cells, cellStyle.Render(glyph))
+			}
+		}
+		if len(cells) == 0 {
+			break
+		}
+		rows = append(rows, lg.NewStyle().Width(width).Background(p.theme.Background).Render(lg.JoinHorizontal(lg.Top, cells...)))
+	}
+
+	infoStyle := lg.NewStyle().Background(p.theme.Background).Foreground(p.theme.Subtle).Width(width)
+
+	var info string
+	if len(p.emojis) == 0 {
+		info = infoStyle.Render("No matching emoji")
+	} else {
+		totalRows := (len(p.emojis) + columns - 1) / columns
+		info = infoStyle.Render(fmt.Sprintf(":%s:  %d/%d", p.emojis[p.selected], selectedRow+1, totalRows))
+	}
+
+	help := infoStyle.Render("\nArrows/Select  Enter/React  Esc/Cancel")
+
+	return lg.JoinVertical(lg.Left, p.input.View(), lg.JoinVertical(lg.Left, rows...), info, help)
+}
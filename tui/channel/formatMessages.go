@@ -9,7 +9,9 @@ import (
 	"time"
 
 	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/utils"
 	lg "github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
 )
 
 func (a *app) formatMessage(mes core.Message, chat *chat) string {
@@ -26,7 +28,12 @@ func (a *app) formatMessage(mes core.Message, chat *chat) string {
 	}
 	timestamp := time.Unix(sec, nsec*1000).Format("15:04")
 
-	text := a.findMentionsInMessageContent(mes.Content)
+	var text string
+	if len(mes.Blocks) > 0 {
+		text = a.renderBlocks(mes, chat.chatWidth-6)
+	} else if mes.Content != "" {
+		text = a.renderMrkdwn(utils.Sanitize(mes.Content), chat.chatWidth-6)
+	}
 
 	type reactionItem struct {
 		emoji string
@@ -52,7 +59,7 @@ func (a *app) formatMessage(mes core.Message, chat *chat) string {
 			Background(a.theme.Background).
 			BorderBackground(a.theme.Background).
 			Foreground(a.theme.Subtle).
-			Render(":" + reaction.emoji + ":" + " " + strconv.Itoa(reaction.count))
+			Render(a.emojiLabel(reaction.emoji) + " " + strconv.Itoa(reaction.count))
 		emojis = append(emojis, emoji)
 	}
 
@@ -63,7 +70,7 @@ func (a *app) formatMessage(mes core.Message, chat *chat) string {
 				Background(a.theme.Background).
 				BorderBackground(a.theme.Background).
 				Foreground(a.theme.Secondary).
-				Render(f.URLPrivate))
+				Render(utils.Sanitize(f.URLPrivate)))
 		}
 	}
 
@@ -139,6 +146,23 @@ func (a *app) formatMessage(mes core.Message, chat *chat) string {
 		bottomBlock = styledText
 	}
 
+	if mes.SubType == "thread_broadcast" && chat == &a.chat {
+		bottomBlock = lg.JoinVertical(lg.Top, a.threadBroadcastHeader(mes, chat.chatWidth-6), bottomBlock)
+	}
+
+	if cards := a.attachmentCards(mes, chat.chatWidth-6); len(cards) > 0 {
+		cardsContainer := lg.NewStyle().
+			Width(chat.chatWidth - 6).
+			Background(a.theme.Background).
+			Render(lg.JoinVertical(lg.Left, cards...))
+
+		if bottomBlock == "" {
+			bottomBlock = cardsContainer
+		} else {
+			bottomBlock = lg.JoinVertical(lg.Top, bottomBlock, cardsContainer)
+		}
+	}
+
 	if len(emojis) > 0 {
 		styledEmojis := lg.NewStyle().
 			Background(a.theme.Background).
@@ -163,8 +187,16 @@ func (a *app) formatMessage(mes core.Message, chat *chat) string {
 
 		bottomBlock = lg.JoinVertical(lg.Top, bottomBlock, linksContainer)
 	}
+	if previews := a.imagePreviews(mes, chat.chatWidth-6); len(previews) > 0 {
+		previewsContainer := lg.NewStyle().
+			Width(chat.chatWidth - 6).
+			Background(a.theme.Background).
+			Render(lg.JoinVertical(lg.Left, previews...))
+
+		bottomBlock = lg.JoinVertical(lg.Top, bottomBlock, previewsContainer)
+	}
 
-	if mes.ReplyCount > 0 {
+	if mes.ReplyCount > 0 && chat == &a.chat {
 		var usernames []string
 		var userIDs []string
 		for _, userID := range mes.ReplyUsers {
@@ -218,24 +250,44 @@ func (a *app) formatMessage(mes core.Message, chat *chat) string {
 	return lg.NewStyle().Width(chat.chatWidth - 2).Render(finalBlock)
 }
 
+func (a *app) threadBroadcastHeader(mes core.Message, width int) string {
+	header := "↳ replied to a thread"
+	for _, parent := range a.chat.messages {
+		if parent.Ts == mes.ThreadId {
+			snippet := utils.PlainMrkdwn(parent.Content,
+				func(id string) string { return a.getUser(id, false) },
+				func(id string) string { return a.getChannel(id, false) },
+				a.Cache.Emoji)
+			header += ": " + strings.Join(strings.Fields(utils.Sanitize(snippet)), " ")
+			break
+		}
+	}
+
+	return lg.NewStyle().
+		Width(width).
+		Background(a.theme.Background).
+		Foreground(a.theme.Muted).
+		Italic(true).
+		Render(runewidth.Truncate(header, width, "…"))
+}
+
 func (a *app) styleUserMention(userMention string) string {
 	username := a.getUser(userMention, false)
 
-	s := lg.NewStyle().
-		Foreground(a.theme.Text).
-		Background(a.theme.Primary).
-		Render("@" + username)
-
-	return s
+	return a.styleMention("@" + username)
 }
 
 func (a *app) styleChannelMention(channelMention string) string {
-	channelName := a.getChannel(channelMention, false)
+	channelName := utils.Sanitize(a.getChannel(channelMention, false))
+
+	return a.styleMention("#" + channelName)
+}
 
+func (a *app) styleMention(mention string) string {
 	s := lg.NewStyle().
 		Foreground(a.theme.Text).
 		Background(a.theme.Primary).
-		Render("#" + channelName)
+		Render(mention)
 
 	return s
 }
@@ -0,0 +1,231 @@
+package channel
+
+import (
+	"fmt"
+	"image"
+	"slices"
+	"strings"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/images"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+const (
+	imagePreviewCols = 40
+	imagePreviewRows = 12
+	imageWorkers     = 3
+	maxCachedImages  = 128
+)
+
+type inlineImage struct {
+	image    image.Image
+	loading  bool
+	failed   bool
+	rendered map[int]string
+}
+
+type imageCache struct {
+	entries  map[string]*inlineImage
+	order    []string
+	pending  []string
+	fetching bool
+}
+
+func newImageCache() imageCache {
+	return imageCache{entries: make(map[string]*inlineImage)}
+}
+
+func (c *imageCache) get(url string) (*inlineImage, bool) {
+	img, ok := c.entries[url]
+	if ok {
+		c.touch(url)
+	}
+	return img, ok
+}
+
+func (c *imageCache) add(url string, img *inlineImage) {
+	c.entries[url] = img
+	c.order = append(c.order, url)
+
+	for len(c.order) > maxCachedImages {
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+}
+
+func (c *imageCache) touch(url string) {
+	if i := slices.Index(c.order, url); i != -1 && i != len(c.order)-1 {
+		c.order = append(slices.Delete(c.order, i, i+1), url)
+	}
+}
+
+func (c *imageCache) clear() {
+	c.entries = make(map[string]*inlineImage)
+	c.order = nil
+	c.pending = nil
+}
+
+func previewURLs(mes core.Message) []string {
+	var urls []string
+	for _, f := range mes.Files {
+		if !strings.HasPrefix(f.Mimetype, "image/") {
+			continue
+		}
+
+		switch {
+		case f.Thumb480 != "":
+			urls = append(urls, f.Thumb480)
+		case f.Thumb360 != "":
+			urls = append(urls, f.Thumb360)
+		case f.URLPrivate != "":
+			urls = append(urls, f.URLPrivate)
+		}
+	}
+
+	for _, attachment := range mes.Attachments {
+		switch {
+		case attachment.ThumbURL != "":
+			urls = append(urls, attachment.ThumbURL)
+		case attachment.ImageURL != "":
+			urls = append(urls, attachment.ImageURL)
+		}
+	}
+	return urls
+}
+
+func blockImageURLs(mes core.Message) []string {
+	var urls []string
+	for _, block := range mes.Blocks {
+		if block.Type == "image" && block.ImageURL != "" {
+			urls = append(urls, block.ImageURL)
+		}
+	}
+	return urls
+}
+
+func (a *app) imagePreviews(mes core.Message, width int) []string {
+	if a.imageProtocol == images.Off || width < 1 {
+		return nil
+	}
+
+	var previews []string
+	for _, url := range previewURLs(mes) {
+		if preview := a.imagePreview(url, width); preview != "" {
+			previews = append(previews, preview)
+		}
+	}
+	return previews
+}
+
+func (a *app) imagePreview(url string, width int) string {
+	preview, ok := a.images.get(url)
+	if !ok {
+		preview = &inlineImage{loading: true, rendered: make(map[int]string)}
+		a.images.add(url, preview)
+		a.images.pending = append(a.images.pending, url)
+	}
+
+	switch {
+	case preview.failed:
+		return ""
+	case preview.loading:
+		return lg.NewStyle().
+			Foreground(a.theme.Muted).
+			Background(a.theme.Background).
+			Italic(true).
+			Render("loading image…")
+	}
+	return a.renderImage(url, preview, min(width, imagePreviewCols))
+}
+
+func (a *app) renderImage(url string, preview *inlineImage, maxCols int) string {
+	if rendered, ok := preview.rendered[maxCols]; ok {
+		return rendered
+	}
+
+	cols, rows := images.Size(preview.image, maxCols, imagePreviewRows)
+	id := images.ID(fmt.Sprintf("%v@%dx%d", url, cols, rows))
+
+	if a.imageProtocol == images.Kitty {
+		img := preview.image
+		go func() {
+			if transmit, err := images.KittyTransmit(id, img, cols, rows); err == nil {
+				a.output.Write([]byte(transmit))
+			}
+		}()
+	}
+
+	rendered := strings.Join(images.Render(a.imageProtocol, id, preview.image, cols, rows), "\n")
+	preview.rendered[maxCols] = rendered
+	return rendered
+}
+
+func (a *app) fetchImages() tea.Cmd {
+	if a.images.fetching {
+		return nil
+	}
+
+	var urls []string
+	for _, url := range a.images.pending {
+		if preview, ok := a.images.entries[url]; ok && preview.loading {
+			urls = append(urls, url)
+		}
+	}
+	a.images.pending = nil
+	if len(urls) == 0 {
+		return nil
+	}
+
+	a.images.fetching = true
+	workspace := a.workspaces[a.activeWorkspace].config
+	return func() tea.Msg {
+		api.ForEach(urls, imageWorkers, func(url string) {
+			a.MsgChan <- api.FetchImage(workspace, url)()
+		})
+		return core.ImagesFetchedMsg{}
+	}
+}
+
+func (a *app) imageLoaded(cmds *[]tea.Cmd, msg core.ImageLoadedMsg) {
+	preview, ok := a.images.entries[msg.URL]
+	if !ok {
+		return
+	}
+
+	preview.loading = false
+	if msg.Err != nil || msg.Image == nil {
+		preview.failed = true
+	} else {
+		preview.image = msg.Image
+	}
+
+	a.refreshImageMessages(cmds, &a.chat, false, msg.URL)
+	if a.threadWindow.isOpen {
+		a.refreshImageMessages(cmds, &a.threadWindow.chat, true, msg.URL)
+	}
+}
+
+func (a *app) refreshImageMessages(cmds *[]tea.Cmd, chat *chat, isThread bool, url string) {
+	if len(chat.displayedMessages) != len(chat.messages) {
+		return
+	}
+
+	var indices []int
+	for i, mes := range chat.messages {
+		if slices.Contains(previewURLs(mes), url) || slices.Contains(blockImageURLs(mes), url) {
+			indices = append(indices, i)
+		}
+	}
+	if len(indices) == 0 {
+		return
+	}
+
+	atBottom := chat.viewport.AtBottom()
+	a.updateMessage(cmds, chat, isThread, indices...)
+	if atBottom {
+		chat.viewport.GotoBottom()
+	}
+}
@@ -0,0 +1,55 @@
+package channel
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Jan-Kur/HackCLI/core"
+)
+
+func TestImageCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newImageCache()
+	for i := range maxCachedImages {
+		cache.add(fmt.Sprint(i), &inlineImage{})
+	}
+
+	if _, ok := cache.get("0"); !ok {
+		t.Fatal("image 0 was evicted too early")
+	}
+	cache.add("new", &inlineImage{})
+
+	if _, ok := cache.entries["1"]; ok {
+		t.Error("the least recently used image wasn't evicted")
+	}
+	if _, ok := cache.entries["0"]; !ok {
+		t.Error("a recently used image was evicted")
+	}
+	if len(cache.entries) != maxCachedImages || len(cache.order) != maxCachedImages {
+		t.Errorf("cache holds %d entries in %d slots, want %d", len(cache.entries), len(cache.order), maxCachedImages)
+	}
+}
+
+func TestFetchImagesRunsOneBatchAtATime(t *testing.T) {
+	a := &app{model: model{
+		images:     newImageCache(),
+		workspaces: []*workspace{{config: core.Workspace{Domain: "test"}}},
+	}}
+
+	a.imagePreview("https://example.com/a.png", 40)
+	a.imagePreview("https://example.com/b.png", 40)
+	if len(a.images.pending) != 2 {
+		t.Fatalf("rendering queued %d fetches, want 2", len(a.images.pending))
+	}
+
+	if a.fetchImages() == nil {
+		t.Fatal("no fetch started for the queued images")
+	}
+	a.imagePreview("https://example.com/c.png", 40)
+	if a.fetchImages() != nil {
+		t.Error("a second batch started while the first one was running")
+	}
+
+	if _, cmd := a.Update(core.ImagesFetchedMsg{}); cmd == nil || !a.images.fetching || len(a.images.pending) != 0 {
+		t.Errorf("the queued image wasn't fetched after the first batch, pending %v", a.images.pending)
+	}
+}
@@ -0,0 +1,284 @@
+package channel
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Jan-Kur/HackCLI/utils"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	maxMentionSuggestions = 6
+	mentionDropdownWidth  = 40
+)
+
+type mentionCompletion struct {
+	isVisible bool
+	query     string
+	results   []mentionSuggestion
+	selected  int
+	tokens    map[string]string
+}
+
+type mentionSuggestion struct {
+	label string
+	token string
+	title string
+	isNew bool
+}
+
+func (a *app) completionKeybinds(msg tea.KeyMsg, input *textarea.Model, completion *mentionCompletion) bool {
+	if !completion.isVisible {
+		return false
+	}
+
+	switch msg.String() {
+	case "up", "ctrl+p":
+		if completion.selected > 0 {
+			completion.selected--
+		}
+	case "down", "ctrl+n":
+		if completion.selected < len(completion.results)-1 {
+			completion.selected++
+		}
+	case "tab", "enter":
+		a.acceptMention(input, completion)
+	case "esc":
+		completion.isVisible = false
+	default:
+		return false
+	}
+	return true
+}
+
+func (a *app) acceptMention(input *textarea.Model, completion *mentionCompletion) {
+	if len(completion.results) == 0 {
+		completion.isVisible = false
+		return
+	}
+	suggestion := completion.results[completion.selected]
+
+	for range []rune(completion.query) {
+		*input, _ = input.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	input.InsertString(suggestion.label + " ")
+
+	if completion.tokens == nil {
+		completion.tokens = make(map[string]string)
+	}
+	completion.tokens[suggestion.label] = suggestion.token
+	completion.isVisible = false
+}
+
+func (a *app) updateCompletion(input *textarea.Model, completion *mentionCompletion) {
+	query, ok := mentionQuery(*input)
+	if !ok {
+		completion.isVisible = false
+		return
+	}
+
+	completion.query = query
+	completion.results = a.mentionSuggestions(query, completion.tokens)
+	completion.selected = 0
+	completion.isVisible = len(completion.results) > 0
+}
+
+func mentionQuery(input textarea.Model) (string, bool) {
+	lines := strings.Split(input.Value(), "\n")
+	if input.Line() >= len(lines) {
+		return "", false
+	}
+
+	info := input.LineInfo()
+	line := []rune(lines[input.Line()])
+	col := min(info.StartColumn+info.ColumnOffset, len(line))
+
+	for i := col - 1; i >= 0; i-- {
+		r := line[i]
+		if r == ' ' || r == '\t' {
+			return "", false
+		}
+		if r == '@' || r == '#' {
+			if i > 0 && isNameRune(line[i-1]) {
+				return "", false
+			}
+			return string(line[i:col]), true
+		}
+	}
+	return "", false
+}
+
+func (a *app) mentionSuggestions(query string, tokens map[string]string) []mentionSuggestion {
+	trigger := query[:1]
+	needle := strings.ToLower(query[1:])
+
+	type candidate struct {
+		suggestion mentionSuggestion
+		rank       int
+	}
+	var candidates []candidate
+
+	rank := func(name string) int {
+		lower := strings.ToLower(name)
+		switch {
+		case strings.HasPrefix(lower, needle):
+			return 0
+		case strings.Contains(lower, needle):
+			return 1
+		}
+		return -1
+	}
+
+	a.Mutex.RLock()
+	if trigger == "@" {
+		nameCounts := make(map[string]int)
+		for _, user := range a.Cache.Users {
+			nameCounts[user.Name]++
+		}
+
+		for _, user := range a.Cache.Users {
+			if user.Name == "" || user.Name == "..." {
+				continue
+			}
+			r := rank(user.Name)
+			if r == -1 {
+				continue
+			}
+
+			token := fmt.Sprintf("<@%s>", user.ID)
+			label := "@" + user.Name
+			if existing, ok := tokens[label]; nameCounts[user.Name] > 1 || (ok && existing != token) {
+				label = fmt.Sprintf("@%s (%s)", user.Name, user.ID)
+			}
+
+			candidates = append(candidates, candidate{
+				suggestion: mentionSuggestion{label: label, token: token, title: "@" + user.Name},
+				rank:       r,
+			})
+		}
+	} else {
+		for _, conv := range a.Cache.Conversations {
+			if strings.HasPrefix(conv.ID, "D") || conv.Name == "" || conv.Name == "..." {
+				continue
+			}
+			r := rank(conv.Name)
+			if r == -1 {
+				continue
+			}
+
+			candidates = append(candidates, candidate{
+				suggestion: mentionSuggestion{label: "#" + conv.Name, token: fmt.Sprintf("<#%s>", conv.ID), title: "#" + conv.Name, isNew: !conv.IsMember},
+				rank:       r,
+			})
+		}
+	}
+	a.Mutex.RUnlock()
+
+	slices.SortFunc(candidates, func(first, second candidate) int {
+		if first.rank != second.rank {
+			return first.rank - second.rank
+		}
+		return strings.Compare(strings.ToLower(first.suggestion.title), strings.ToLower(second.suggestion.title))
+	})
+
+	var suggestions []mentionSuggestion
+	for _, c := range candidates {
+		if len(suggestions) == maxMentionSuggestions {
+			break
+		}
+		suggestions = append(suggestions, c.suggestion)
+	}
+	return suggestions
+}
+
+func (c *mentionCompletion) apply(content string) string {
+	labels := make([]string, 0, len(c.tokens))
+	for label := range c.tokens {
+		labels = append(labels, label)
+	}
+	slices.SortFunc(labels, func(first, second string) int {
+		return len(second) - len(first)
+	})
+
+	var b strings.Builder
+	for i := 0; i < len(content); {
+		label := ""
+		if startsMention(content[:i]) {
+			for _, candidate := range labels {
+				if strings.HasPrefix(content[i:], candidate) && endsMention(content[i+len(candidate):]) {
+					label = candidate
+					break
+				}
+			}
+		}
+
+		if label != "" {
+			b.WriteString(c.tokens[label])
+			i += len(label)
+			continue
+		}
+		b.WriteByte(content[i])
+		i++
+	}
+
+	c.tokens = nil
+	c.isVisible = false
+	return b.String()
+}
+
+func startsMention(before string) bool {
+	prev, _ := utf8.DecodeLastRuneInString(before)
+	return before == "" || !isNameRune(prev)
+}
+
+func endsMention(rest string) bool {
+	next, size := utf8.DecodeRuneInString(rest)
+	switch {
+	case rest == "":
+		return true
+	case isNameRune(next):
+		return false
+	case next == '.':
+		after, _ := utf8.DecodeRuneInString(rest[size:])
+		return !isNameRune(after)
+	}
+	return true
+}
+
+func isNameRune(r rune) bool {
+	return isWordRune(r) || r == '_' || r == '-'
+}
+
+func (a *app) completionView(completion mentionCompletion) string {
+	var rows []string
+
+	for i, suggestion := range completion.results {
+		style := lg.NewStyle().
+			Width(mentionDropdownWidth).
+			Foreground(a.theme.Subtle).
+			Background(a.theme.Background)
+		if i == completion.selected {
+			style = style.Foreground(a.theme.Selected).Bold(true)
+		}
+
+		title := utils.Sanitize(suggestion.label)
+		if suggestion.isNew {
+			title += " (not joined)"
+		}
+
+		rows = append(rows, style.Render(runewidth.Truncate(title, mentionDropdownWidth, "…")))
+	}
+
+	return lg.NewStyle().
+		Border(lg.RoundedBorder(), true).
+		BorderForeground(a.theme.Selected).
+		BorderBackground(a.theme.Background).
+		Background(a.theme.Background).
+		Render(lg.JoinVertical(lg.Left, rows...))
+}
@@ -0,0 +1,48 @@
+package channel
+
+import "testing"
+
+func TestMentionCompletionApply(t *testing.T) {
+	tokens := map[string]string{
+		"@al":          "<@U1>",
+		"@alice":       "<@U2>",
+		"@bob (U3)":    "<@U3>",
+		"#general":     "<#C1>",
+		"@dev-ops.bot": "<@U4>",
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"single", "hi @alice", "hi <@U2>"},
+		{"longest label wins", "@al and @alice", "<@U1> and <@U2>"},
+		{"typed longer name", "@alfred is not @al", "@alfred is not <@U1>"},
+		{"inside a word", "mail me at me@alice.com", "mail me at me@alice.com"},
+		{"punctuation after", "@alice, @al. #general!", "<@U2>, <@U1>. <#C1>!"},
+		{"parentheses", "(@alice) (#general)", "(<@U2>) (<#C1>)"},
+		{"quotes", "\"@alice\" and '@al'", "\"<@U2>\" and '<@U1>'"},
+		{"after an underscore", "x_@alice", "x_@alice"},
+		{"line start", "@bob (U3)\n#general!", "<@U3>\n<#C1>!"},
+		{"dotted name", "ping @dev-ops.bot.", "ping <@U4>."},
+		{"channel prefix", "#general-chat and #general", "#general-chat and <#C1>"},
+		{"multibyte text", "héllo @alice ✨", "héllo <@U2> ✨"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			completion := mentionCompletion{tokens: make(map[string]string)}
+			for label, token := range tokens {
+				completion.tokens[label] = token
+			}
+
+			if got := completion.apply(test.content); got != test.want {
+				t.Errorf("apply(%q) = %q, want %q", test.content, got, test.want)
+			}
+			if completion.tokens != nil {
+				t.Error("apply kept the tokens around")
+			}
+		})
+	}
+}
@@ -1,13 +1,17 @@
 package channel
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"slices"
 	"strings"
 	"time"
 
 	"github.com/Jan-Kur/HackCLI/api"
 	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/images"
+	"github.com/Jan-Kur/HackCLI/notify"
 	"github.com/Jan-Kur/HackCLI/tui/styles"
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
@@ -32,22 +36,56 @@ type model struct {
 	focused                   FocusState
 	width, height             int
 	sidebarWidth, inputHeight int
+	connection                core.ConnectionState
+	retryAt                   time.Time
+	completion                mentionCompletion
+	store                     *api.Store
+	loadingProgress           core.LoadingProgressMsg
+	workspaces                []*workspace
+	activeWorkspace           int
+	notifier                  notify.Backend
+	terminalBlurred           bool
+	uploading                 core.UploadProgressMsg
+	downloading               core.DownloadProgressMsg
+	imageProtocol             images.Protocol
+	images                    imageCache
+	saver                     *cacheSaver
+	output                    *terminal
+	stopEvents                context.CancelFunc
 }
 
 type threadWindow struct {
-	isOpen   bool
-	parentTs string
-	chat     chat
-	input    textarea.Model
+	isOpen      bool
+	parentTs    string
+	focusTs     string
+	cursor      string
+	hasMore     bool
+	loadingMore bool
+	broadcast   bool
+	chat        chat
+	input       textarea.Model
+	completion  mentionCompletion
 }
 
 type popup struct {
-	theme     styles.Theme
-	overlay   *overlay.Model
-	input     textarea.Model
-	isVisible bool
-	popupType PopupType
-	targetMes core.Message
+	theme        styles.Theme
+	overlay      *overlay.Model
+	input        textarea.Model
+	isVisible    bool
+	popupType    PopupType
+	targetMes    core.Message
+	results      []switcherResult
+	threads      []threadRow
+	activity     []activityRow
+	upload       uploadState
+	fileStatus   string
+	actions      []actionRow
+	actionStatus string
+	emojis       []string
+	customEmoji  map[string]string
+	selected     int
+	search       searchState
+	browser      browserState
 }
 
 type errorPopup struct {
@@ -64,6 +102,7 @@ var (
 	focusedChatStyle styles.BoxWithLabel
 	threadChatStyle  lg.Style
 	threadInputStyle lg.Style
+	threadLabelStyle lg.Style
 )
 
 type FocusState int
@@ -81,8 +120,15 @@ type PopupType int
 const (
 	PopupReaction PopupType = iota
 	PopupEdit
-	PopupJoinChannel
 	PopupError
+	PopupSwitcher
+	PopupSearch
+	PopupThreads
+	PopupActivity
+	PopupUpload
+	PopupFiles
+	PopupActions
+	PopupBrowser
 )
 
 const (
@@ -92,17 +138,47 @@ const (
 	ThreadWindowWidth = 0.35
 	minHeight         = 10
 	minWidth          = 50
+	historyPageSize   = 100
+	loadingWorkers    = 8
 )
 
 func (a *app) Init() tea.Cmd {
 	if !a.InitialLoading {
-		return api.GetChannelHistory(a.Client, a.CurrentChannel)
+		var cmds []tea.Cmd
+		a.loadChannelHistory(&cmds)
+		return tea.Batch(cmds...)
 	} else {
 		return nil
 	}
 }
 
+func (a *app) Output() io.Writer {
+	return a.output
+}
+
+func (a *app) Close() {
+	if a.stopEvents != nil {
+		a.stopEvents()
+	}
+
+	a.flushCaches()
+	close(a.saver.writes)
+	<-a.saver.done
+
+	for _, ws := range a.workspaces {
+		ws.store.Close()
+	}
+}
+
 func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := a.update(msg)
+	if fetch := a.fetchImages(); fetch != nil {
+		cmd = tea.Batch(cmd, fetch)
+	}
+	return model, cmd
+}
+
+func (a *app) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 
@@ -114,7 +190,30 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				a.popup.isVisible = false
 				a.popup.input.Blur()
 				return a, nil
+			}
 
+			switch a.popup.popupType {
+			case PopupSwitcher:
+				return a.switcherKeybinds(msg)
+			case PopupReaction:
+				return a.emojiPickerKeybinds(msg)
+			case PopupSearch:
+				return a.searchKeybinds(msg)
+			case PopupThreads:
+				return a.threadsKeybinds(msg)
+			case PopupActivity:
+				return a.activityKeybinds(msg)
+			case PopupUpload:
+				return a.uploadKeybinds(msg)
+			case PopupFiles:
+				return a.filesKeybinds(msg)
+			case PopupActions:
+				return a.actionsKeybinds(msg)
+			case PopupBrowser:
+				return a.browserKeybinds(msg)
+			}
+
+			switch msg.String() {
 			case "alt+enter":
 				mes := a.popup.targetMes
 				content := a.popup.input.Value()
@@ -124,41 +223,6 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					a.popup.input.Reset()
 					a.popup.isVisible = false
 					return a, nil
-
-				case PopupReaction:
-					if users, ok := mes.Reactions[content]; ok && slices.Contains(users, a.User) {
-						go func() {
-							err := a.Client.RemoveReaction(content, slack.ItemRef{
-								Channel:   a.CurrentChannel,
-								Timestamp: mes.Ts,
-							})
-							if err == nil {
-								a.MsgChan <- core.ReactionScrollMsg{Added: false}
-							}
-						}()
-					} else {
-						go func() {
-							err := a.Client.AddReaction(content, slack.ItemRef{
-								Channel:   a.CurrentChannel,
-								Timestamp: mes.Ts,
-							})
-							if err == nil {
-								a.MsgChan <- core.ReactionScrollMsg{Added: true}
-							}
-						}()
-					}
-					a.popup.input.Reset()
-					a.popup.isVisible = false
-					return a, nil
-				case PopupJoinChannel:
-					go func() {
-						_, _, _, err := a.Client.JoinConversation(content)
-						a.showErrorPopup(fmt.Sprintf("Error joining channel: %v", err))
-					}()
-
-					a.popup.input.Reset()
-					a.popup.isVisible = false
-					return a, nil
 				}
 			default:
 				a.popup.input, cmd = a.popup.input.Update(msg)
@@ -166,9 +230,35 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		switch a.focused {
+		case FocusInput:
+			if a.completionKeybinds(msg, &a.input, &a.completion) {
+				return a, nil
+			}
+		case FocusThreadInput:
+			if a.completionKeybinds(msg, &a.threadWindow.input, &a.threadWindow.completion) {
+				return a, nil
+			}
+		}
+
 		switch msg.String() {
 		case "esc":
 			return a, tea.Quit
+		case "ctrl+k":
+			a.openSwitcher()
+			return a, nil
+		case "ctrl+f":
+			a.openSearch()
+			return a, nil
+		case "alt+d":
+			a.toggleDoNotDisturb()
+			return a, nil
+		case "alt+w":
+			a.switchWorkspace((a.activeWorkspace+1)%len(a.workspaces), &cmds)
+			return a, tea.Batch(cmds...)
+		case "alt+1", "alt+2", "alt+3", "alt+4", "alt+5", "alt+6", "alt+7", "alt+8", "alt+9":
+			a.switchWorkspace(int(msg.String()[4]-'1'), &cmds)
+			return a, tea.Batch(cmds...)
 		case "tab":
 			if a.threadWindow.isOpen {
 				a.focused = (a.focused + 1) % 5
@@ -184,41 +274,132 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case core.ChannelSelectedMsg:
 		a.CurrentChannel = msg.Id
+		a.sidebar.focusItem(msg.Id)
 		a.chat.messages = []core.Message{}
+		a.chat.hasMore = false
+		a.chat.hasNewer = false
+		a.chat.loadingOlder = false
+		a.chat.loadingNewer = false
 		a.threadWindow.chat.messages = []core.Message{}
 		a.threadWindow.isOpen = false
 		a.threadWindow.parentTs = ""
+		a.threadWindow.focusTs = ""
+
+		if msg.Ts == "" {
+			a.loadChannelHistory(&cmds)
+			break
+		}
+
+		focusTs := msg.Ts
+		if msg.ThreadTs != "" {
+			focusTs = msg.ThreadTs
+		}
+		if a.Offline {
+			cmds = append(cmds, a.getStoredHistoryAround(a.CurrentChannel, focusTs))
+		} else {
+			cmds = append(cmds, api.GetHistoryAround(a.Client, a.CurrentChannel, focusTs))
+		}
+
+		a.focused = FocusChat
+		if msg.ThreadTs == "" {
+			break
+		}
 
-		cmd = api.GetChannelHistory(a.Client, a.CurrentChannel)
-		cmds = append(cmds, cmd)
+		a.focused = FocusThreadChat
+		a.openThread(&cmds, msg.ThreadTs, msg.Ts)
 
 	case core.HistoryLoadedMsg:
-		a.chat.messages = append(msg.Messages, a.chat.messages...)
-		slices.SortFunc(a.chat.messages, sortingMessagesAlgorithm)
+		if msg.ChannelID != a.CurrentChannel {
+			return a, nil
+		}
+
+		if msg.Older {
+			a.chat.hasMore = msg.HasMore
+			a.chat.loadingOlder = false
+			if !a.Offline {
+				a.store.SaveMessages(msg.ChannelID, msg.Messages...)
+			}
+			if len(msg.Messages) > 0 {
+				a.prependMessages(&cmds, msg.Messages)
+				cmds = append(cmds, a.getHistoryUsersCmd())
+			}
+			return a, tea.Batch(cmds...)
+		}
+
+		if msg.Newer {
+			a.chat.hasNewer = msg.HasNewer
+			a.chat.loadingNewer = false
+			a.store.SaveMessages(msg.ChannelID, msg.Messages...)
+			if len(msg.Messages) > 0 {
+				a.appendMessages(&cmds, msg.Messages)
+				cmds = append(cmds, a.getHistoryUsersCmd())
+			}
+			if msg.LatestTs != "" {
+				a.markChannelRead(msg.LatestTs)
+			}
+			return a, tea.Batch(cmds...)
+		}
+
+		a.chat.hasMore = msg.HasMore
+		a.chat.hasNewer = msg.HasNewer
+
+		if !a.Offline {
+			a.store.ReconcileHistory(msg.ChannelID, msg.Messages)
+		}
+		a.chat.messages = mergeHistory(a.chat.messages, msg.Messages, msg.HasMore)
 
 		cmds = append(cmds, a.getHistoryUsersCmd())
 
 		a.chat.selectedMessage = len(a.chat.messages) - 1
+		if msg.FocusTs != "" {
+			for i, mes := range a.chat.messages {
+				if mes.Ts <= msg.FocusTs {
+					a.chat.selectedMessage = i
+				}
+			}
+		}
+
 		a.renderChat(&cmds, &a.chat, false)
 		if a.chat.viewport.Height > 0 {
-			a.chat.viewport.GotoBottom()
+			if msg.FocusTs != "" {
+				scrollToMessage(&a.chat, a.chat.selectedMessage)
+			} else {
+				a.chat.viewport.GotoBottom()
+			}
 		}
 
 		if msg.LatestTs != "" {
-			a.Cache.Conversations[a.CurrentChannel].LastRead = msg.LatestTs
-			a.Cache.Conversations[a.CurrentChannel].LatestMessage = msg.LatestTs
-			go func() {
-				if err := a.Client.MarkConversation(a.CurrentChannel, msg.LatestTs); err != nil {
-					a.showErrorPopup(fmt.Sprintf("Error marking conversation as read: %v", err))
-				}
-			}()
+			a.markChannelRead(msg.LatestTs)
 		}
 	case core.ThreadLoadedMsg:
-		a.threadWindow.chat.messages = msg.Messages
-		a.threadWindow.chat.selectedMessage = len(a.threadWindow.chat.messages) - 1
+		if msg.ChannelID != a.CurrentChannel || msg.ThreadTs != a.threadWindow.parentTs {
+			return a, nil
+		}
+
+		a.threadWindow.cursor = msg.Cursor
+		a.threadWindow.hasMore = msg.HasMore
+		a.threadWindow.loadingMore = false
+
+		if msg.More {
+			a.threadWindow.chat.messages = mergeReplies(a.threadWindow.chat.messages, msg.Messages)
+		} else {
+			a.threadWindow.chat.messages = msg.Messages
+			a.threadWindow.chat.selectedMessage = len(a.threadWindow.chat.messages) - 1
+		}
+
+		focusFound := false
+		for i, mes := range a.threadWindow.chat.messages {
+			if mes.Ts == a.threadWindow.focusTs {
+				a.threadWindow.chat.selectedMessage = i
+				focusFound = true
+			}
+		}
+		if a.threadWindow.focusTs != "" && !focusFound && msg.HasMore {
+			a.loadMoreReplies(&cmds)
+		}
 
 		for i, mes := range a.chat.messages {
-			if mes.Ts == a.threadWindow.parentTs {
+			if mes.Ts == a.threadWindow.parentTs && !msg.HasMore {
 				a.chat.messages[i].ReplyCount = len(a.threadWindow.chat.messages) - 1
 
 				var users []string
@@ -229,20 +410,74 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				a.chat.messages[i].ReplyUsers = users
 				a.updateMessage(&cmds, &a.chat, false, i)
+				a.store.SaveMessages(a.CurrentChannel, a.chat.messages[i])
 				break
 			}
 		}
+		if len(a.threadWindow.chat.messages) > 1 {
+			a.store.SaveMessages(a.CurrentChannel, a.threadWindow.chat.messages[1:]...)
+		}
+		if !msg.More && len(msg.Messages) > 0 && msg.Messages[0].Ts == msg.ThreadTs {
+			a.updateThreadParent(msg.ChannelID, msg.ThreadTs, msg.Messages[0].User, msg.Messages[0].Content)
+		}
 
 		a.renderChat(&cmds, &a.threadWindow.chat, true)
-		if a.threadWindow.chat.viewport.Height > 0 {
-			a.threadWindow.chat.viewport.GotoBottom()
+		if a.threadWindow.chat.viewport.Height > 0 && !msg.More {
+			if a.threadWindow.focusTs != "" {
+				scrollToMessage(&a.threadWindow.chat, a.threadWindow.chat.selectedMessage)
+			} else {
+				a.threadWindow.chat.viewport.GotoBottom()
+			}
+		} else if focusFound {
+			scrollToMessage(&a.threadWindow.chat, a.threadWindow.chat.selectedMessage)
+		}
+		if focusFound || !msg.HasMore {
+			a.threadWindow.focusTs = ""
+		}
+	case core.ThreadsFoundMsg:
+		if msg.Workspace != a.Cache.Workspace {
+			return a, nil
 		}
+
+		a.mergeFoundThreads(msg.Threads)
+		if a.popup.isVisible && a.popup.popupType == PopupThreads {
+			a.updateThreadRows()
+			a.loadThreadParents(&cmds)
+		}
+		return a, tea.Batch(cmds...)
+	case core.ActivityFoundMsg:
+		if msg.Workspace != a.Cache.Workspace {
+			return a, nil
+		}
+		a.mergeFoundActivity(msg.Items)
+	case core.DownloadProgressMsg:
+		a.downloading = msg
+	case core.DownloadFinishedMsg:
+		a.downloadFinished(msg)
+	case core.UploadProgressMsg:
+		a.uploading = msg
+	case core.UploadFinishedMsg:
+		if a.uploading.Name == msg.Name {
+			a.uploading = core.UploadProgressMsg{}
+		}
+		if msg.Err != nil {
+			go a.showErrorPopup(fmt.Sprintf("Error uploading %v: %v", msg.Name, msg.Err))
+		}
+	case core.ImageLoadedMsg:
+		a.imageLoaded(&cmds, msg)
+	case core.ImagesFetchedMsg:
+		a.images.fetching = false
+	case core.ThreadParentLoadedMsg:
+		a.updateThreadParent(msg.ChannelID, msg.ThreadTs, msg.User, msg.Text)
 	case core.NewMessageMsg:
 		goToBottom := false
+		a.store.SaveMessages(a.CurrentChannel, msg.Message)
 
-		if msg.Message.ThreadId == "" || msg.Message.Ts == msg.Message.ThreadId {
+		isReply := msg.Message.ThreadId != "" && msg.Message.Ts != msg.Message.ThreadId
+
+		if !isReply || msg.Message.SubType == "thread_broadcast" {
 			previousLastMessage := len(a.chat.messages) - 1
-			if previousLastMessage != -1 {
+			if previousLastMessage != -1 && !a.chat.hasNewer {
 				a.insertMessage(msg.Message, &a.chat)
 				if a.chat.viewport.AtBottom() {
 					goToBottom = true
@@ -255,7 +490,10 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					a.chat.viewport.GotoBottom()
 				}
 			}
-		} else {
+		}
+
+		if isReply {
+			goToBottom = false
 			parentTs := msg.Message.ThreadId
 
 			for i, mes := range a.chat.messages {
@@ -265,6 +503,7 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						a.chat.messages[i].ReplyUsers = append(a.chat.messages[i].ReplyUsers, msg.Message.User)
 					}
 					a.updateMessage(&cmds, &a.chat, false, i)
+					a.store.SaveMessages(a.CurrentChannel, a.chat.messages[i])
 					break
 				}
 			}
@@ -287,9 +526,17 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 	case core.EditedMessageMsg:
+		a.store.UpdateMessage(a.CurrentChannel, msg.Ts, func(mes *core.Message) {
+			mes.Content = msg.Content
+			mes.Attachments = msg.Attachments
+			mes.Blocks = msg.Blocks
+		})
+
 		for i, mes := range a.chat.messages {
 			if mes.Ts == msg.Ts {
 				a.chat.messages[i].Content = msg.Content
+				a.chat.messages[i].Attachments = msg.Attachments
+				a.chat.messages[i].Blocks = msg.Blocks
 				a.updateMessage(&cmds, &a.chat, false, i)
 				break
 			}
@@ -299,12 +546,16 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			for i, mes := range a.threadWindow.chat.messages {
 				if mes.Ts == msg.Ts {
 					a.threadWindow.chat.messages[i].Content = msg.Content
+					a.threadWindow.chat.messages[i].Attachments = msg.Attachments
+					a.threadWindow.chat.messages[i].Blocks = msg.Blocks
 					a.updateMessage(&cmds, &a.threadWindow.chat, true, i)
 					break
 				}
 			}
 		}
 	case core.DeletedMessageMsg:
+		a.store.DeleteMessage(a.CurrentChannel, msg.DeletedTs)
+
 		for i, mes := range a.chat.messages {
 			if mes.Ts == msg.DeletedTs {
 				if mes.ThreadId != mes.Ts {
@@ -345,27 +596,48 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 	case core.ReactionAddedMsg:
+		a.store.UpdateMessage(a.CurrentChannel, msg.MessageTs, func(mes *core.Message) {
+			if mes.Reactions == nil {
+				mes.Reactions = make(map[string][]string)
+			}
+			if !slices.Contains(mes.Reactions[msg.Reaction], msg.User) {
+				mes.Reactions[msg.Reaction] = append(mes.Reactions[msg.Reaction], msg.User)
+			}
+		})
+
 		for i, mes := range a.chat.messages {
 			if mes.Ts == msg.MessageTs {
+				if a.chat.messages[i].Reactions == nil {
+					a.chat.messages[i].Reactions = make(map[string][]string)
+				}
 				reaction := a.chat.messages[i].Reactions[msg.Reaction]
-				reaction = append(reaction, msg.User)
-				a.chat.messages[i].Reactions[msg.Reaction] = reaction
-				a.updateMessage(&cmds, &a.chat, false, i)
+				if !slices.Contains(reaction, msg.User) {
+					a.chat.messages[i].Reactions[msg.Reaction] = append(reaction, msg.User)
+					a.updateMessage(&cmds, &a.chat, false, i)
+				}
 				break
 			}
 		}
 		if a.threadWindow.isOpen {
 			for i, mes := range a.threadWindow.chat.messages {
 				if mes.Ts == msg.MessageTs {
+					if a.threadWindow.chat.messages[i].Reactions == nil {
+						a.threadWindow.chat.messages[i].Reactions = make(map[string][]string)
+					}
 					reaction := a.threadWindow.chat.messages[i].Reactions[msg.Reaction]
-					reaction = append(reaction, msg.User)
-					a.threadWindow.chat.messages[i].Reactions[msg.Reaction] = reaction
-					a.updateMessage(&cmds, &a.threadWindow.chat, true, i)
+					if !slices.Contains(reaction, msg.User) {
+						a.threadWindow.chat.messages[i].Reactions[msg.Reaction] = append(reaction, msg.User)
+						a.updateMessage(&cmds, &a.threadWindow.chat, true, i)
+					}
 					break
 				}
 			}
 		}
 	case core.ReactionRemovedMsg:
+		a.store.UpdateMessage(a.CurrentChannel, msg.MessageTs, func(mes *core.Message) {
+			delete(mes.Reactions, msg.Reaction)
+		})
+
 		for i, mes := range a.chat.messages {
 			if mes.Ts == msg.MessageTs {
 				delete(a.chat.messages[i].Reactions, msg.Reaction)
@@ -390,10 +662,17 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		})
 
 	case core.InsertChannelInSidebarMsg:
+		if slices.ContainsFunc(a.sidebar.items, func(item sidebarItem) bool { return item.id == msg.ChannelID }) {
+			break
+		}
+
 		startIndex := 1
 		var endIndex int
 
 		for i, item := range a.sidebar.items {
+			if item.isHeader && strings.Contains(item.title, "CHANNELS") {
+				startIndex = i + 1
+			}
 			if item.isHeader && strings.Contains(item.title, "DMs") {
 				endIndex = i
 				break
@@ -418,6 +697,63 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		a.rerenderSidebar()
 
+	case core.EmojiLoadedMsg:
+		ws := a.workspace(msg.Workspace)
+		if ws == nil {
+			break
+		}
+
+		a.Mutex.Lock()
+		ws.cache.Emoji = msg.Emoji
+		a.Mutex.Unlock()
+
+		a.saveCache(ws.cache)
+		if !a.isActive(ws) {
+			break
+		}
+
+		a.renderChat(&cmds, &a.chat, false)
+		if a.threadWindow.isOpen {
+			a.renderChat(&cmds, &a.threadWindow.chat, true)
+		}
+
+	case core.InsertDMInSidebarMsg:
+		dm := msg.DM
+		a.Cache.Conversations[dm.ID] = &dm
+		a.saveCache(a.Cache)
+
+		startIndex := len(a.sidebar.items)
+		for i, item := range a.sidebar.items {
+			if item.id == dm.ID {
+				return a, nil
+			}
+			if item.isHeader && strings.Contains(item.title, "DMs") {
+				startIndex = i + 1
+			}
+		}
+
+		insertIndex := len(a.sidebar.items)
+		for i := startIndex; i < len(a.sidebar.items); i++ {
+			if strings.Compare(dm.User.Name, a.sidebar.items[i].title) < 0 {
+				insertIndex = i
+				break
+			}
+		}
+
+		a.sidebar.items = slices.Insert(a.sidebar.items, insertIndex, sidebarItem{
+			title:  dm.User.Name,
+			id:     dm.ID,
+			userID: dm.User.ID,
+		})
+		if a.sidebar.selectedItem >= insertIndex {
+			a.sidebar.selectedItem++
+		}
+		if a.sidebar.openChannel >= insertIndex {
+			a.sidebar.openChannel++
+		}
+
+		a.rerenderSidebar()
+
 	case core.ChannelLeftMsg:
 		for i, channel := range a.sidebar.items {
 			if channel.id == msg.Channel {
@@ -436,21 +772,15 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case core.UserInfoLoadedMsg:
-		if msg.User != nil {
-			username := msg.User.Profile.DisplayName
-			if username == "" {
-				if firstName := msg.User.Profile.FirstName; firstName != "" {
-					username = firstName
-				} else {
-					username = msg.User.RealName
-				}
-			}
+		ws := a.workspace(msg.Workspace)
+		if msg.User != nil && ws != nil {
+			a.Mutex.Lock()
+			ws.cache.Users[msg.User.ID] = &core.User{ID: msg.User.ID, Name: sanitize(api.DisplayName(msg.User))}
+			a.Mutex.Unlock()
 
-			a.Cache.Users[msg.User.ID] = &core.User{ID: msg.User.ID, Name: sanitize(username)}
+			a.saveCache(ws.cache)
 
-			go api.SaveCache(*a.Cache)
-
-			if !msg.IsHistory {
+			if !msg.IsHistory && a.isActive(ws) {
 				userMentionPattern := fmt.Sprintf(`<@%s`, msg.User.ID)
 
 				var indices []int
@@ -479,6 +809,13 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					a.updateMessage(&cmds, &a.chat, false, indices...)
 				}
 
+				if a.popup.isVisible && a.popup.popupType == PopupSearch {
+					a.renderSearchResults()
+				}
+				if a.popup.isVisible && a.popup.popupType == PopupBrowser && a.popup.browser.previewing {
+					a.renderChannelPreview()
+				}
+
 				if a.threadWindow.isOpen {
 					var threadIndices []int
 					for i, mes := range a.threadWindow.chat.messages {
@@ -493,14 +830,24 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 	case core.ChannelInfoLoadedMsg:
-		a.Cache.Conversations[msg.Channel.ID] = &core.Conversation{
+		ws := a.workspace(msg.Workspace)
+		if ws == nil {
+			break
+		}
+
+		a.Mutex.Lock()
+		ws.cache.Conversations[msg.Channel.ID] = &core.Conversation{
 			ID:            msg.Channel.ID,
 			Name:          msg.Channel.Name,
 			LastRead:      msg.Channel.LastRead,
 			LatestMessage: msg.LatestMes,
 		}
+		a.Mutex.Unlock()
 
-		go api.SaveCache(*a.Cache)
+		a.saveCache(ws.cache)
+		if !a.isActive(ws) {
+			break
+		}
 
 		channelMentionPattern := fmt.Sprintf(`<#%s`, msg.Channel.ID)
 
@@ -527,11 +874,7 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case core.HandleEventMsg:
 		switch ev := msg.Event.(type) {
 		case *api.MessageEvent:
-			if ev.SubType == "" && (ev.ThreadTimestamp == "" || ev.Timestamp == ev.ThreadTimestamp) {
-				if ev.Channel == a.CurrentChannel {
-					a.Cache.Conversations[ev.Channel].LastRead = ev.Timestamp
-				}
-			}
+			a.trackUnread(ev)
 
 			if ev.Channel == a.CurrentChannel {
 				api.MessageHandler(a.MsgChan, ev)
@@ -574,40 +917,105 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case core.ChannelReadMsg:
-		a.Cache.Conversations[msg.ChannelID].LastRead = msg.LastRead
-		a.Cache.Conversations[msg.ChannelID].LatestMessage = msg.LatestTs
-		go api.SaveCache(*a.Cache)
-		a.rerenderSidebar()
+		ws := a.workspace(msg.Workspace)
+		if ws == nil {
+			return a, nil
+		}
+		conv, ok := ws.cache.Conversations[msg.ChannelID]
+		if !ok {
+			return a, nil
+		}
+
+		a.Mutex.Lock()
+		conv.LastRead = msg.LastRead
+		conv.LatestMessage = msg.LatestTs
+		if conv.LastRead >= conv.LatestMessage {
+			conv.UnreadCount = 0
+			conv.MentionCount = 0
+		}
+		a.Mutex.Unlock()
+
+		a.saveCache(ws.cache)
+		if a.isActive(ws) {
+			a.rerenderSidebar()
+		}
 
 	case core.PresenceChangedMsg:
 		a.Cache.Conversations[msg.DmID].UserPresence = msg.Presence
-		go api.SaveCache(*a.Cache)
+		a.saveCache(a.Cache)
 		a.rerenderSidebar()
 
 	case core.WaitMsg:
 		return a, tea.Tick(msg.Duration, func(t time.Time) tea.Msg {
 			return msg.Msg
 		})
+	case core.SaveCacheMsg:
+		a.flushCaches()
+	case core.SearchResultsMsg:
+		a.handleSearchResults(msg)
+	case core.ChannelsListedMsg:
+		return a, a.handleChannelsListed(msg)
+	case core.ChannelPreviewMsg:
+		a.handleChannelPreview(msg)
+	case core.LoadingProgressMsg:
+		if msg.Workspace == a.Cache.Workspace {
+			a.loadingProgress = msg
+		}
 	case core.CloseErrorPopupMsg:
 		a.errorPopup.isVisible = false
 		a.errorPopup.err = ""
+	case core.WorkspaceEventMsg:
+		return a.handleWorkspaceEvent(msg)
+	case core.ConnectionStateMsg:
+		a.connection = msg.State
+		a.retryAt = time.Time{}
+		if msg.RetryIn > 0 {
+			a.retryAt = time.Now().Add(msg.RetryIn)
+			cmds = append(cmds, retryTick(a.retryAt))
+		}
+
+		if msg.Reconnected && !a.InitialLoading {
+			a.backfill(&cmds)
+		}
+	case core.RetryTickMsg:
+		if msg.RetryAt.Equal(a.retryAt) && a.connection == core.Disconnected && time.Until(a.retryAt) > 0 {
+			cmds = append(cmds, retryTick(a.retryAt))
+		}
 	case core.FetchedCacheMsg:
+		ws := a.workspace(msg.Workspace)
+		if ws == nil {
+			break
+		}
+
+		a.Mutex.Lock()
 		for _, conv := range msg.Conversations {
-			a.Cache.Conversations[conv.ID] = conv
+			ws.cache.Conversations[conv.ID] = conv
 		}
 
 		for _, user := range msg.Users {
-			a.Cache.Users[user.ID] = user
+			ws.cache.Users[user.ID] = user
 		}
+		a.Mutex.Unlock()
+
+		a.saveCache(ws.cache)
 
-		go api.SaveCache(*a.Cache)
+		if !ws.loading {
+			break
+		}
+		ws.loading = false
 
-		if a.InitialLoading {
+		if a.isActive(ws) {
 			a.initializeSidebar(msg.SidebarChannels, msg.SidebarDms)
-			cmds = append(cmds, api.GetChannelHistory(a.Client, a.CurrentChannel))
+			a.loadChannelHistory(&cmds)
 			a.InitialLoading = false
+		} else {
+			ws.sidebar, ws.currentChannel = a.newSidebar(msg.SidebarChannels, msg.SidebarDms, ws.currentChannel)
 		}
 
+	case tea.FocusMsg:
+		a.terminalBlurred = false
+	case tea.BlurMsg:
+		a.terminalBlurred = true
 	case tea.WindowSizeMsg:
 		a.width = msg.Width
 		a.height = msg.Height
@@ -661,16 +1069,24 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
 			switch keyMsg.String() {
 			case "j":
-				a.popup.popupType = PopupJoinChannel
-				a.popup.input.SetHeight(1)
-				a.popup.input.ShowLineNumbers = false
-				a.popup.input.Placeholder = "C09AHK61U8G"
-				a.popup.input.SetWidth(50)
-				a.popup.input.Reset()
-				a.popup.isVisible = true
-				a.popup.input.Focus()
+				return a, a.openBrowser()
+			case "enter":
+				switch a.sidebar.items[a.sidebar.selectedItem].id {
+				case threadsItemID:
+					a.openThreads(&cmds)
+					return a, tea.Batch(cmds...)
+				case activityItemID:
+					a.openActivity(&cmds)
+					return a, tea.Batch(cmds...)
+				}
 			case "l":
-				a.Client.LeaveConversation(a.sidebar.items[a.sidebar.selectedItem].id)
+				if item := a.sidebar.items[a.sidebar.selectedItem]; !item.isView() {
+					a.Client.LeaveConversation(item.id)
+				}
+			case "m":
+				if item := a.sidebar.items[a.sidebar.selectedItem]; !item.isHeader && !item.isView() {
+					a.toggleMute(item.id)
+				}
 			}
 		}
 		a.sidebar, focusCmd = a.sidebar.Update(msg)
@@ -682,14 +1098,24 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		a.chat.viewport, focusCmd = a.chat.viewport.Update(msg)
+		if isScrollUp(msg) && a.chat.viewport.AtTop() {
+			a.loadOlderMessages(&cmds)
+		}
+		if isScrollDown(msg) && a.chat.viewport.AtBottom() {
+			a.loadNewerMessages(&cmds)
+		}
 		a.input.Blur()
 	case FocusInput:
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
 			switch keyMsg.String() {
+			case "alt+a":
+				a.openUpload("")
+				return a, nil
 			case "alt+enter":
 				content := a.input.Value()
 				if strings.TrimSpace(content) != "" {
 					a.input.Reset()
+					content = a.completion.apply(content)
 					go a.SendMessage(content)
 					return a, nil
 				}
@@ -698,6 +1124,7 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		a.input, focusCmd = a.input.Update(msg)
 		a.input.Focus()
+		a.updateCompletion(&a.input, &a.completion)
 	case FocusThreadChat:
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
 			if a.chatKeybinds(keyMsg.String(), &cmds, true, &a.threadWindow.chat) {
@@ -705,6 +1132,9 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 		a.threadWindow.chat.viewport, focusCmd = a.threadWindow.chat.viewport.Update(msg)
+		if isScrollDown(msg) && a.threadWindow.chat.viewport.AtBottom() {
+			a.loadMoreReplies(&cmds)
+		}
 		a.threadWindow.input.Blur()
 	case FocusThreadInput:
 		if keyMsg, ok := msg.(tea.KeyMsg); ok {
@@ -713,13 +1143,26 @@ func (a *app) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				content := a.threadWindow.input.Value()
 				if strings.TrimSpace(content) != "" {
 					a.threadWindow.input.Reset()
-					go a.SendReply(content, a.threadWindow.parentTs)
+					content = a.threadWindow.completion.apply(content)
+					go a.SendReply(content, a.threadWindow.parentTs, a.threadWindow.broadcast)
+					a.threadWindow.broadcast = false
 					return a, nil
 				}
 			}
 		}
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			switch keyMsg.String() {
+			case "ctrl+o":
+				a.threadWindow.broadcast = !a.threadWindow.broadcast
+				return a, nil
+			case "alt+a":
+				a.openUpload(a.threadWindow.parentTs)
+				return a, nil
+			}
+		}
 		a.threadWindow.input, focusCmd = a.threadWindow.input.Update(msg)
 		a.threadWindow.input.Focus()
+		a.updateCompletion(&a.threadWindow.input, &a.threadWindow.completion)
 	}
 	cmds = append(cmds, focusCmd)
 
@@ -741,7 +1184,7 @@ func (a *app) View() string {
 ██║     ██║   ██║██╔══██║██║  ██║██║██║╚██╗██║██║   ██║
 ███████╗╚██████╔╝██║  ██║██████╔╝██║██║ ╚████║╚██████╔╝
 ╚══════╝ ╚═════╝ ╚═╝  ╚═╝╚═════╝ ╚═╝╚═╝  ╚═══╝ ╚═════╝ 
-`)
+` + a.loadingStatus())
 		return s
 	}
 
@@ -776,6 +1219,18 @@ func (a *app) View() string {
 		)
 	}
 
+	if a.focused == FocusInput && a.completion.isVisible {
+		bg := background{view: s}
+		fg := background{view: a.completionView(a.completion)}
+		s = overlay.New(fg, bg, overlay.Left, overlay.Bottom, a.sidebarWidth+1, -a.inputHeight+1).View()
+	}
+
+	if a.focused == FocusThreadInput && a.threadWindow.completion.isVisible {
+		bg := background{view: s}
+		fg := background{view: a.completionView(a.threadWindow.completion)}
+		s = overlay.New(fg, bg, overlay.Left, overlay.Bottom, a.sidebarWidth+a.chat.chatWidth+1, -a.inputHeight+1).View()
+	}
+
 	if a.popup.isVisible {
 		if a.errorPopup.isVisible {
 			bg := background{view: s}
@@ -0,0 +1,290 @@
+package channel
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Jan-Kur/HackCLI/utils"
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+type mrkdwnStyle struct {
+	bold   bool
+	italic bool
+	strike bool
+	code   bool
+}
+
+var listItemRegex = regexp.MustCompile(`^(\s*)([•◦▪\-*]|\d+[.)])\s+(.*)$`)
+
+func (a *app) renderMrkdwn(text string, width int) string {
+	var blocks []string
+
+	segments := splitCodeFences(text)
+	for i, segment := range segments {
+		if i%2 == 1 {
+			blocks = append(blocks, a.renderCodeBlock(segment, width))
+			continue
+		}
+
+		if i > 0 {
+			segment = strings.TrimPrefix(segment, "\n")
+		}
+		if i < len(segments)-1 {
+			segment = strings.TrimSuffix(segment, "\n")
+		}
+		if segment == "" && len(segments) > 1 {
+			continue
+		}
+
+		blocks = append(blocks, a.renderTextLines(segment, width)...)
+	}
+
+	return strings.Join(blocks, "\n")
+}
+
+func splitCodeFences(text string) []string {
+	segments := strings.Split(text, "```")
+
+	if len(segments)%2 == 0 {
+		last := len(segments) - 1
+		segments[last-1] = segments[last-1] + "```" + segments[last]
+		segments = segments[:last]
+	}
+
+	return segments
+}
+
+func (a *app) renderTextLines(text string, width int) []string {
+	var rendered []string
+	var quote []string
+
+	flushQuote := func() {
+		if len(quote) > 0 {
+			rendered = append(rendered, a.renderQuote(quote, width))
+			quote = nil
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if content, ok := quoteContent(line); ok {
+			quote = append(quote, content)
+			continue
+		}
+		flushQuote()
+
+		if match := listItemRegex.FindStringSubmatch(line); match != nil {
+			rendered = append(rendered, a.renderListItem(match[1], match[2], match[3]))
+			continue
+		}
+
+		rendered = append(rendered, a.renderInline(line))
+	}
+	flushQuote()
+
+	return rendered
+}
+
+func quoteContent(line string) (string, bool) {
+	for _, prefix := range []string{"&gt;", ">"} {
+		if content, ok := strings.CutPrefix(line, prefix); ok {
+			return strings.TrimPrefix(content, " "), true
+		}
+	}
+	return "", false
+}
+
+func (a *app) renderQuote(lines []string, width int) string {
+	var rendered []string
+	for _, line := range lines {
+		rendered = append(rendered, a.renderTextLines(line, width-2)...)
+	}
+
+	return lg.NewStyle().
+		Border(lg.Border{Left: "▌"}, false, false, false, true).
+		BorderForeground(a.theme.Subtle).
+		BorderBackground(a.theme.Background).
+		Background(a.theme.Background).
+		PaddingLeft(1).
+		Width(max(1, width-1)).
+		Render(strings.Join(rendered, "\n"))
+}
+
+func (a *app) renderListItem(indent, marker, content string) string {
+	bullet := marker
+	switch marker {
+	case "-", "*", "•":
+		bullet = "•"
+	}
+
+	depth := len(strings.ReplaceAll(indent, "\t", "    ")) / 4
+
+	markerStyle := lg.NewStyle().Foreground(a.theme.Subtle).Background(a.theme.Background)
+	prefix := a.inlineStyle(mrkdwnStyle{}).Render(strings.Repeat("  ", depth+1))
+
+	return prefix + markerStyle.Render(bullet+" ") + a.renderInline(content)
+}
+
+func (a *app) renderCodeBlock(code string, width int) string {
+	code = strings.TrimPrefix(code, "\n")
+	code = strings.TrimSuffix(code, "\n")
+
+	return lg.NewStyle().
+		Foreground(a.theme.Text).
+		Background(a.theme.Border).
+		Padding(0, 1).
+		Width(max(1, width)).
+		Render(utils.DecodeEntities(code))
+}
+
+func (a *app) renderInline(text string) string {
+	var b strings.Builder
+	a.writeInline(&b, text, mrkdwnStyle{})
+	return b.String()
+}
+
+func (a *app) writeInline(b *strings.Builder, text string, style mrkdwnStyle) {
+	var plain strings.Builder
+
+	flush := func() {
+		if plain.Len() > 0 {
+			text := utils.ReplaceShortcodes(utils.DecodeEntities(plain.String()), a.Cache.Emoji)
+			b.WriteString(a.inlineStyle(style).Render(text))
+			plain.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+
+		switch c {
+		case '`':
+			if j := strings.IndexByte(text[i+1:], '`'); j > 0 {
+				flush()
+				codeStyle := style
+				codeStyle.code = true
+				b.WriteString(a.inlineStyle(codeStyle).Render(utils.DecodeEntities(text[i+1 : i+1+j])))
+				i += j + 2
+				continue
+			}
+		case '<':
+			if j := strings.IndexByte(text[i:], '>'); j > 1 {
+				flush()
+				b.WriteString(a.renderToken(text[i+1 : i+j]))
+				i += j + 1
+				continue
+			}
+		case '*', '_', '~':
+			if canOpenSpan(text, i) {
+				if j := findClosingSpan(text, i); j > 0 {
+					flush()
+					inner := style
+					switch c {
+					case '*':
+						inner.bold = true
+					case '_':
+						inner.italic = true
+					case '~':
+						inner.strike = true
+					}
+					a.writeInline(b, text[i+1:j], inner)
+					i = j + 1
+					continue
+				}
+			}
+		}
+
+		plain.WriteByte(c)
+		i++
+	}
+	flush()
+}
+
+func canOpenSpan(text string, i int) bool {
+	if i+1 >= len(text) || text[i+1] == ' ' || text[i+1] == text[i] {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	prev, _ := utf8.DecodeLastRuneInString(text[:i])
+	return !isWordRune(prev)
+}
+
+func findClosingSpan(text string, i int) int {
+	marker := text[i]
+
+	for j := i + 2; j < len(text); j++ {
+		switch text[j] {
+		case '\n':
+			return -1
+		case '<':
+			if k := strings.IndexByte(text[j:], '>'); k > 0 {
+				j += k
+			}
+		case marker:
+			if text[j-1] == ' ' {
+				continue
+			}
+			if j+1 == len(text) {
+				return j
+			}
+			next, _ := utf8.DecodeRuneInString(text[j+1:])
+			if !isWordRune(next) {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func (a *app) inlineStyle(style mrkdwnStyle) lg.Style {
+	s := lg.NewStyle().
+		Foreground(a.theme.Text).
+		Background(a.theme.Background).
+		Bold(style.bold).
+		Italic(style.italic).
+		Strikethrough(style.strike)
+
+	if style.code {
+		s = s.Foreground(a.theme.Secondary).Background(a.theme.Muted)
+	}
+
+	return s
+}
+
+func (a *app) renderToken(token string) string {
+	value, label, _ := strings.Cut(token, "|")
+
+	switch {
+	case strings.HasPrefix(value, "@"):
+		return a.styleUserMention(strings.TrimPrefix(value, "@"))
+	case strings.HasPrefix(value, "#"):
+		if label != "" {
+			return a.styleMention("#" + label)
+		}
+		return a.styleChannelMention(strings.TrimPrefix(value, "#"))
+	case strings.HasPrefix(value, "!subteam^"):
+		if label != "" {
+			return a.styleMention(label)
+		}
+		return a.styleMention("@group")
+	case strings.HasPrefix(value, "!date^"):
+		return a.inlineStyle(mrkdwnStyle{}).Render(utils.DecodeEntities(label))
+	case strings.HasPrefix(value, "!"):
+		return a.styleMention("@" + strings.TrimPrefix(value, "!"))
+	case strings.HasPrefix(value, "http://"), strings.HasPrefix(value, "https://"), strings.HasPrefix(value, "mailto:"):
+		if label != "" {
+			return a.styleLink(utils.DecodeEntities(label))
+		}
+		return a.styleLink(utils.DecodeEntities(value))
+	}
+
+	return a.inlineStyle(mrkdwnStyle{}).Render(utils.DecodeEntities("<" + token + ">"))
+}
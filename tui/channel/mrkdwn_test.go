@@ -0,0 +1,105 @@
+package channel
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/tui/styles"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func newMrkdwnApp() *app {
+	lg.SetColorProfile(termenv.TrueColor)
+
+	return &app{
+		model: model{theme: styles.Themes["Rose Pine"]},
+		App: core.App{
+			Cache: &core.Cache{
+				Users:         map[string]*core.User{"U2": {ID: "U2", Name: "alice"}},
+				Conversations: map[string]*core.Conversation{"C1": {ID: "C1", Name: "general"}},
+			},
+		},
+	}
+}
+
+func TestRenderMrkdwn(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want func(a *app) []string
+	}{
+		{"bold", "*hi*", func(a *app) []string {
+			return []string{a.inlineStyle(mrkdwnStyle{bold: true}).Render("hi")}
+		}},
+		{"italic", "_hi_", func(a *app) []string {
+			return []string{a.inlineStyle(mrkdwnStyle{italic: true}).Render("hi")}
+		}},
+		{"strike", "~hi~", func(a *app) []string {
+			return []string{a.inlineStyle(mrkdwnStyle{strike: true}).Render("hi")}
+		}},
+		{"nested", "*bold _both_*", func(a *app) []string {
+			return []string{
+				a.inlineStyle(mrkdwnStyle{bold: true}).Render("bold "),
+				a.inlineStyle(mrkdwnStyle{bold: true, italic: true}).Render("both"),
+			}
+		}},
+		{"intraword markers", "snake_case_name and 2*3*4", func(a *app) []string {
+			return []string{a.inlineStyle(mrkdwnStyle{}).Render("snake_case_name and 2*3*4")}
+		}},
+		{"code", "run `a &lt; *b*`", func(a *app) []string {
+			return []string{
+				a.inlineStyle(mrkdwnStyle{}).Render("run "),
+				a.inlineStyle(mrkdwnStyle{code: true}).Render("a < *b*"),
+			}
+		}},
+		{"pre", "before\n```\nif a &lt; b {\n}\n```\nafter", func(a *app) []string {
+			return []string{
+				a.inlineStyle(mrkdwnStyle{}).Render("before"),
+				a.renderCodeBlock("if a &lt; b {\n}", 40),
+				a.inlineStyle(mrkdwnStyle{}).Render("after"),
+			}
+		}},
+		{"quote", "&gt; quoted *text*\nplain", func(a *app) []string {
+			return []string{
+				a.renderQuote([]string{"quoted *text*"}, 40),
+				a.inlineStyle(mrkdwnStyle{}).Render("plain"),
+			}
+		}},
+		{"labelled link", "<https://example.com|the site>", func(a *app) []string {
+			return []string{a.styleLink("the site")}
+		}},
+		{"bare link", "<https://example.com?a=1&amp;b=2>", func(a *app) []string {
+			return []string{a.styleLink("https://example.com?a=1&b=2")}
+		}},
+		{"user mention", "hey <@U2> and <@U2|alice>", func(a *app) []string {
+			return []string{a.inlineStyle(mrkdwnStyle{}).Render("hey "), a.styleMention("@alice")}
+		}},
+		{"channel mention", "<#C1> <#C9|random>", func(a *app) []string {
+			return []string{a.styleMention("#general"), a.styleMention("#random")}
+		}},
+		{"broadcast", "<!here> <!subteam^S1|@devs>", func(a *app) []string {
+			return []string{a.styleMention("@here"), a.styleMention("@devs")}
+		}},
+		{"entities", "a &lt;b&gt; &amp;amp; c", func(a *app) []string {
+			return []string{a.inlineStyle(mrkdwnStyle{}).Render("a <b> &amp; c")}
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := newMrkdwnApp()
+
+			got := a.renderMrkdwn(test.text, 40)
+			for _, want := range test.want(a) {
+				if !strings.Contains(got, want) {
+					t.Errorf("renderMrkdwn(%q) = %q, missing %q", test.text, got, want)
+				}
+			}
+			if strings.Contains(got, "```") || strings.Contains(got, "&lt;") || strings.Contains(got, "&gt;") {
+				t.Errorf("renderMrkdwn(%q) = %q, left markup behind", test.text, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package channel
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/Jan-Kur/HackCLI/notify"
+	"github.com/Jan-Kur/HackCLI/utils"
+)
+
+func (a *app) notifyMessage(ws *workspace, ev *api.MessageEvent) {
+	switch ev.SubType {
+	case "", "thread_broadcast", "file_share", "me_message":
+	default:
+		return
+	}
+
+	active := ws == a.workspaces[a.activeWorkspace]
+	if ev.User == "" || ev.User == ws.user || (active && ev.Channel == a.CurrentChannel && !a.terminalBlurred) {
+		return
+	}
+
+	if !notify.ShouldNotify(a.Config.Notifications, ws.user, ev.Channel, ev.Text) {
+		return
+	}
+
+	cache := ws.cache
+	if active {
+		cache = a.Cache
+	}
+
+	a.Mutex.RLock()
+	defer a.Mutex.RUnlock()
+
+	userName := func(userID string) string {
+		if user, ok := cache.Users[userID]; ok && user.Name != "..." {
+			return user.Name
+		}
+		return userID
+	}
+	channelName := func(channelID string) string {
+		if conv, ok := cache.Conversations[channelID]; ok && conv.Name != "" {
+			return conv.Name
+		}
+		return channelID
+	}
+
+	title := "#" + channelName(ev.Channel)
+	if strings.HasPrefix(ev.Channel, "D") {
+		title = userName(ev.User)
+	}
+	if ev.ThreadTimestamp != "" && ev.ThreadTimestamp != ev.Timestamp {
+		title += " (thread)"
+	}
+	if len(a.workspaces) > 1 {
+		title = ws.name() + " · " + title
+	}
+
+	body := userName(ev.User) + ": " + utils.PlainMrkdwn(ev.Text, userName, channelName, cache.Emoji)
+
+	go a.notifier.Send(notify.Notification{Title: title, Body: notify.Body(body)})
+}
+
+func (a *app) toggleMute(channelID string) {
+	notifications := &a.Config.Notifications
+
+	if i := slices.Index(notifications.Muted, channelID); i != -1 {
+		notifications.Muted = slices.Delete(notifications.Muted, i, i+1)
+	} else {
+		notifications.Muted = append(notifications.Muted, channelID)
+	}
+
+	go api.SaveConfig(a.Config)
+	a.rerenderSidebar()
+}
+
+func (a *app) toggleDoNotDisturb() {
+	a.Config.Notifications.DoNotDisturb = !a.Config.Notifications.DoNotDisturb
+	go api.SaveConfig(a.Config)
+}
+
+func (a *app) doNotDisturbIndicator() string {
+	if a.Config.Notifications.DoNotDisturb {
+		return " · do not disturb"
+	}
+	return ""
+}
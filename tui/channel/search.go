@@ -0,0 +1,258 @@
+package channel
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/images"
+	"github.com/Jan-Kur/HackCLI/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+type searchState struct {
+	chat      chat
+	results   []core.SearchResult
+	query     string
+	page      int
+	pageCount int
+	total     int
+	loading   bool
+	err       string
+}
+
+var searchModifierRegex = regexp.MustCompile(`\b(in|from|to):([@#][^\s]+)`)
+
+func (a *app) openSearch() {
+	width := min(max(50, a.width*7/10), a.width-6)
+
+	a.popup.popupType = PopupSearch
+	a.popup.input.SetHeight(1)
+	a.popup.input.ShowLineNumbers = false
+	a.popup.input.Placeholder = "Search messages (in:#channel from:@user before: after: has:link)"
+	a.popup.input.SetWidth(width)
+	a.popup.input.Reset()
+	a.popup.input.SetValue(a.popup.search.query)
+	a.popup.isVisible = true
+	a.popup.input.Focus()
+
+	a.popup.search.chat.chatWidth = width + 2
+	a.popup.search.chat.viewport.Width = width
+	a.popup.search.chat.viewport.Height = max(5, a.height*6/10)
+	a.renderSearchResults()
+}
+
+func (a *app) searchKeybinds(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	search := &a.popup.search
+
+	switch msg.String() {
+	case "up", "ctrl+p":
+		if search.chat.selectedMessage > 0 {
+			search.chat.selectedMessage--
+			a.renderSearchResults()
+		}
+	case "down", "ctrl+n":
+		if search.chat.selectedMessage < len(search.results)-1 {
+			search.chat.selectedMessage++
+			a.renderSearchResults()
+		} else {
+			return a, a.loadMoreSearchResults()
+		}
+	case "enter", "alt+enter":
+		query := strings.TrimSpace(a.popup.input.Value())
+		if query == "" {
+			return a, nil
+		}
+
+		if query != search.query || len(search.results) == 0 {
+			search.query = query
+			search.results = nil
+			search.page = 1
+			search.pageCount = 0
+			search.total = 0
+			search.loading = true
+			search.err = ""
+			search.chat.selectedMessage = 0
+			a.renderSearchResults()
+			return a, api.SearchMessages(a.Client, a.searchQuery(query), 1)
+		}
+
+		result := search.results[search.chat.selectedMessage]
+
+		a.popup.input.Blur()
+		a.popup.isVisible = false
+
+		return a, func() tea.Msg {
+			return core.ChannelSelectedMsg{
+				Id:       result.ChannelID,
+				Ts:       result.Message.Ts,
+				ThreadTs: parentTs(result.Message),
+			}
+		}
+	default:
+		a.popup.input, cmd = a.popup.input.Update(msg)
+	}
+	return a, cmd
+}
+
+func (a *app) loadMoreSearchResults() tea.Cmd {
+	search := &a.popup.search
+	if search.loading || search.page >= search.pageCount {
+		return nil
+	}
+
+	search.loading = true
+	a.renderSearchResults()
+	return api.SearchMessages(a.Client, a.searchQuery(search.query), search.page+1)
+}
+
+func (a *app) searchQuery(query string) string {
+	return searchModifierRegex.ReplaceAllStringFunc(query, func(modifier string) string {
+		match := searchModifierRegex.FindStringSubmatch(modifier)
+		return match[1] + ":" + a.getMentionID(match[2])
+	})
+}
+
+func (a *app) handleSearchResults(msg core.SearchResultsMsg) {
+	search := &a.popup.search
+	if a.searchQuery(search.query) != msg.Query {
+		return
+	}
+
+	search.loading = false
+	if msg.Err != nil {
+		search.err = fmt.Sprintf("Search failed: %v", msg.Err)
+		a.renderSearchResults()
+		return
+	}
+
+	if msg.Page <= 1 {
+		search.results = msg.Results
+		search.chat.selectedMessage = 0
+	} else {
+		search.results = append(search.results, msg.Results...)
+	}
+	search.page = msg.Page
+	search.pageCount = msg.PageCount
+	search.total = msg.Total
+
+	a.renderSearchResults()
+}
+
+func (a *app) renderSearchResults() {
+	search := &a.popup.search
+
+	search.chat.messages = make([]core.Message, len(search.results))
+	for i, result := range search.results {
+		search.chat.messages[i] = result.Message
+	}
+
+	search.chat.displayedMessages = make([]string, len(search.results))
+	for i, result := range search.results {
+		header := lg.NewStyle().
+			Width(search.chat.chatWidth - 2).
+			Foreground(a.theme.Subtle).
+			Background(a.theme.Background).
+			Render(a.searchResultLocation(result))
+
+		search.chat.displayedMessages[i] = header + "\n" + a.formatMessage(result.Message, &search.chat) + "\n"
+	}
+
+	search.chat.viewport.SetContent(lg.JoinVertical(lg.Top, search.chat.displayedMessages...))
+	ensureMessageVisible(&search.chat)
+}
+
+func (a *app) searchResultLocation(result core.SearchResult) string {
+	location := "#" + utils.Sanitize(result.ChannelName)
+	if strings.HasPrefix(result.ChannelID, "D") {
+		location = "@" + a.getUser(result.ChannelName, false)
+	}
+
+	if parentTs(result.Message) != "" {
+		location += " · in thread"
+	}
+
+	sec, err := strconv.ParseInt(strings.Split(result.Message.Ts, ".")[0], 10, 64)
+	if err == nil {
+		location += " · " + time.Unix(sec, 0).Format("Jan 2, 2006")
+	}
+
+	return location
+}
+
+func parentTs(mes core.Message) string {
+	if mes.ThreadId != "" && mes.ThreadId != mes.Ts {
+		return mes.ThreadId
+	}
+	return ""
+}
+
+func ensureMessageVisible(chat *chat) {
+	if chat.selectedMessage < 0 || chat.selectedMessage >= len(chat.displayedMessages) {
+		return
+	}
+
+	offset := 0
+	for _, displayed := range chat.displayedMessages[:chat.selectedMessage] {
+		offset += lg.Height(displayed)
+	}
+	height := lg.Height(chat.displayedMessages[chat.selectedMessage])
+
+	if offset < chat.viewport.YOffset {
+		chat.viewport.SetYOffset(offset)
+	} else if offset+height > chat.viewport.YOffset+chat.viewport.Height {
+		chat.viewport.SetYOffset(offset + height - chat.viewport.Height)
+	}
+}
+
+func scrollToMessage(chat *chat, index int) {
+	if index < 0 || index >= len(chat.displayedMessages) {
+		return
+	}
+
+	offset := 0
+	for _, displayed := range chat.displayedMessages[:index] {
+		offset += lg.Height(displayed)
+	}
+	chat.viewport.SetYOffset(offset)
+}
+
+func (p popup) searchView() string {
+	search := p.search
+	width := p.input.Width()
+
+	infoStyle := lg.NewStyle().Background(p.theme.Background).Foreground(p.theme.Subtle).Width(width)
+
+	var status string
+	switch {
+	case search.err != "":
+		status = search.err
+	case search.loading && len(search.results) == 0:
+		status = "Searching..."
+	case search.query == "":
+		status = "Type a query and press Enter"
+	case len(search.results) == 0:
+		status = "No results"
+	default:
+		status = fmt.Sprintf("%d/%d of %d results · page %d/%d", search.chat.selectedMessage+1, len(search.results), search.total, search.page, search.pageCount)
+		if search.loading {
+			status += " · loading more..."
+		}
+	}
+
+	results := lg.NewStyle().
+		Width(width).
+		Height(search.chat.viewport.Height).
+		Background(p.theme.Background).
+		Render(images.ClipSixel(search.chat.viewport.View()))
+
+	help := infoStyle.Render("\n↑↓/Select  Enter/Search or Open  Esc/Close")
+
+	return lg.JoinVertical(lg.Left, p.input.View(), infoStyle.Render(status), results, help)
+}
@@ -1,39 +1,80 @@
 package channel
 
 import (
+	"context"
 	"fmt"
-	"net/http"
+	"os"
+	"sync"
 
 	"github.com/Jan-Kur/HackCLI/api"
 	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/images"
+	"github.com/Jan-Kur/HackCLI/notify"
 	"github.com/Jan-Kur/HackCLI/tui/styles"
-	"github.com/Jan-Kur/HackCLI/utils"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/slack-go/slack"
 )
 
-func Start(initialChannel string) *app {
+type terminal struct {
+	*os.File
+	mu sync.Mutex
+}
+
+func (t *terminal) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.File.Write(p)
+}
+
+func Start(initialChannel string, workspaceName string, offline bool) *app {
 	cfg, err := api.LoadConfig()
 	if err != nil {
 		panic(fmt.Sprintf("Couldn't load config: %v", err))
 	}
 
-	firstRun := false
-	cache := api.LoadCache()
-	if cache == nil {
-		cache = &core.Cache{
-			Conversations: make(map[string]*core.Conversation),
-			Users:         make(map[string]*core.User),
+	activeConfig, err := api.FindWorkspace(cfg, workspaceName)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	var workspaces []*workspace
+	var active int
+	var failed []string
+
+	for _, config := range cfg.Workspaces {
+		ws, err := openWorkspace(config, offline)
+		if err != nil {
+			if config.Domain != activeConfig.Domain {
+				failed = append(failed, err.Error())
+				continue
+			}
+			if offline {
+				panic(fmt.Sprintf("Nothing to browse offline yet: %v", err))
+			}
+			panic(fmt.Sprintf("Couldn't connect to Slack, use --offline to browse stored messages: %v", err))
+		}
+
+		if config.Domain == activeConfig.Domain {
+			active = len(workspaces)
 		}
-		firstRun = true
+		workspaces = append(workspaces, ws)
 	}
 
-	httpCl := utils.NewCookieHTTP("https://slack.com", utils.ConvertCookies([]http.Cookie{{Name: "d", Value: cfg.Cookie}}))
-	client := slack.New(cfg.Token, slack.OptionHTTPClient(httpCl))
+	cfg.ActiveWorkspace = activeConfig.Domain
 
-	user, _ := client.AuthTest()
+	a := newApp(cfg, workspaces, active, initialChannel, offline)
 
+	for _, err := range failed {
+		go a.showErrorPopup(err)
+	}
+
+	return a
+}
+
+func newApp(cfg core.Config, workspaces []*workspace, active int, initialChannel string, offline bool) *app {
 	msgChan := make(chan tea.Msg)
+	output := &terminal{File: os.Stdout}
+	ws := workspaces[active]
+	ws.loaded = true
 
 	a := &app{
 		model: model{
@@ -52,6 +93,7 @@ func Start(initialChannel string) *app {
 				isVisible: false,
 			},
 			theme: styles.Themes[cfg.Theme],
+			store: ws.store,
 			threadWindow: threadWindow{
 				isOpen: false,
 				chat: chat{
@@ -59,22 +101,41 @@ func Start(initialChannel string) *app {
 				},
 				input: initializeInput(styles.Themes[cfg.Theme]),
 			},
+			workspaces:      workspaces,
+			activeWorkspace: active,
+			notifier:        notify.New(cfg.Notifications, output),
+			imageProtocol:   images.Detect(cfg.Images),
+			images:          newImageCache(),
+			saver:           newCacheSaver(),
+			output:          output,
 		},
 		App: core.App{
-			User:           user.UserID,
+			User:           ws.user,
 			Config:         cfg,
-			Cache:          cache,
-			InitialLoading: firstRun,
+			Cache:          ws.cache,
+			InitialLoading: ws.loading,
 			CurrentChannel: initialChannel,
-			Client:         client,
+			Client:         ws.client,
+			Events:         ws.events,
 			MsgChan:        msgChan,
+			Offline:        offline,
 		},
 	}
 	InitializeStyles(a.theme)
 
-	a.LoadConversations()
+	a.LoadConversations(ws)
 
-	go api.RunWebsocket(a.Config.Token, a.Config.Cookie, a.MsgChan)
+	if offline {
+		return a
+	}
+
+	go a.loadCustomEmoji(ws)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.stopEvents = cancel
+	for _, ws := range a.workspaces {
+		go a.runEvents(ctx, ws)
+	}
 
 	return a
 }
@@ -0,0 +1,202 @@
+package channel
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/Jan-Kur/HackCLI/api/fake"
+	"github.com/Jan-Kur/HackCLI/core"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type fakeApp struct {
+	*app
+	msgs chan tea.Msg
+}
+
+func newFakeApp(t *testing.T, w *fake.Workspace, initialChannel string) *fakeApp {
+	return newFakeWorkspacesApp(t, []*fake.Workspace{w}, initialChannel)
+}
+
+func newFakeWorkspacesApp(t *testing.T, fakes []*fake.Workspace, initialChannel string) *fakeApp {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	cfg := core.Config{
+		Theme:         "Rose Pine",
+		Notifications: core.NotificationConfig{Backend: "none"},
+		Images:        "off",
+	}
+
+	var workspaces []*workspace
+	for i, w := range fakes {
+		config := core.Workspace{Name: fmt.Sprintf("Test %d", i), Domain: fmt.Sprintf("test%d", i)}
+
+		store, err := api.OpenStore(config.Domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		workspaces = append(workspaces, &workspace{
+			config:  config,
+			client:  w,
+			events:  w.Events(),
+			cache:   &core.Cache{Users: map[string]*core.User{}, Conversations: map[string]*core.Conversation{}, UserID: "U1", Workspace: config.Domain},
+			store:   store,
+			user:    "U1",
+			loading: true,
+		})
+		cfg.Workspaces = append(cfg.Workspaces, config)
+	}
+	cfg.ActiveWorkspace = cfg.Workspaces[0].Domain
+
+	a := &fakeApp{
+		app:  newApp(cfg, workspaces, 0, initialChannel, false),
+		msgs: make(chan tea.Msg, 256),
+	}
+	t.Cleanup(a.Close)
+
+	go func() {
+		for msg := range a.MsgChan {
+			a.msgs <- msg
+		}
+	}()
+
+	a.run(a.Update(tea.WindowSizeMsg{Width: 120, Height: 40}))
+	return a
+}
+
+func (a *fakeApp) run(_ tea.Model, cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	go func() {
+		switch msg := cmd().(type) {
+		case nil:
+		case tea.BatchMsg:
+			for _, cmd := range msg {
+				a.run(nil, cmd)
+			}
+		default:
+			a.MsgChan <- msg
+		}
+	}()
+}
+
+func (a *fakeApp) waitFor(t *testing.T, what string, done func() bool) {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for !done() {
+		select {
+		case msg := <-a.msgs:
+			a.run(a.Update(msg))
+		case <-timeout:
+			t.Fatalf("timed out waiting for %v", what)
+		}
+	}
+}
+
+func (a *app) hasMessage(user, text string) bool {
+	return slices.ContainsFunc(a.chat.messages, func(mes core.Message) bool {
+		return mes.User == user && mes.Content == text
+	})
+}
+
+func TestAppFlow(t *testing.T) {
+	w := fake.NewWorkspace("U1", "me")
+	w.AddUser("U2", "alice")
+	w.AddChannel("C1", "general", false, "U1", "U2")
+	for _, text := range []string{"first", "second", "third"} {
+		if _, err := w.AddMessage("C1", "U2", text, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a := newFakeApp(t, w, "C1")
+
+	a.waitFor(t, "the channel history", func() bool {
+		return !a.InitialLoading && len(a.chat.messages) == 3
+	})
+	for i, text := range []string{"first", "second", "third"} {
+		if a.chat.messages[i].Content != text {
+			t.Fatalf("message %d = %q, want %q", i, a.chat.messages[i].Content, text)
+		}
+	}
+
+	a.focused = FocusInput
+	a.input.SetValue("hello there")
+	a.run(a.Update(tea.KeyMsg{Type: tea.KeyEnter, Alt: true}))
+	a.waitFor(t, "the sent message", func() bool { return a.hasMessage("U1", "hello there") })
+
+	if a.input.Value() != "" {
+		t.Fatalf("input = %q after sending, want it empty", a.input.Value())
+	}
+
+	if _, err := w.Receive("C1", "U2", "ping <@U1|me>", ""); err != nil {
+		t.Fatal(err)
+	}
+	a.waitFor(t, "the received message", func() bool { return a.hasMessage("U2", "ping <@U1|me>") })
+	a.waitFor(t, "the mention in activity", func() bool {
+		return slices.ContainsFunc(a.Cache.Activity, func(item *core.ActivityItem) bool { return item.Kind == "mention" })
+	})
+
+	sent := a.chat.messages[3].Ts
+	if _, err := w.Receive("C1", "U2", "a reply", sent); err != nil {
+		t.Fatal(err)
+	}
+	a.waitFor(t, "the thread to be tracked", func() bool {
+		thread, ok := a.Cache.Threads[threadKey("C1", sent)]
+		return ok && thread.LatestUser == "U2"
+	})
+
+	if got := len(a.chat.messages); got != 5 {
+		t.Fatalf("chat has %d messages, want 5", got)
+	}
+	if mes := a.chat.messages[len(a.chat.messages)-1]; mes.Content != "ping <@U1|me>" {
+		t.Fatalf("last message = %q, want the received one", mes.Content)
+	}
+}
+
+func (s sidebar) has(channelID string) bool {
+	return slices.ContainsFunc(s.items, func(item sidebarItem) bool { return item.id == channelID })
+}
+
+func TestSwitchWorkspaceWhileLoading(t *testing.T) {
+	first := fake.NewWorkspace("U1", "me")
+	first.AddChannel("C1", "general", false, "U1")
+	first.AddMessage("C1", "U1", "hi", "")
+	second := fake.NewWorkspace("U1", "me")
+	second.AddChannel("C2", "random", false, "U1")
+	second.AddMessage("C2", "U1", "hi", "")
+
+	a := newFakeWorkspacesApp(t, []*fake.Workspace{first, second}, "")
+	a.waitFor(t, "the first workspace", func() bool { return !a.InitialLoading })
+
+	var cmds []tea.Cmd
+	a.switchWorkspace(1, &cmds)
+	if !a.InitialLoading {
+		t.Fatal("the second workspace isn't loading")
+	}
+	a.switchWorkspace(0, &cmds)
+	if a.InitialLoading {
+		t.Fatal("switching back to a loaded workspace kept the loading screen")
+	}
+
+	loading := a.workspaces[1]
+	a.waitFor(t, "the second workspace", func() bool { return !loading.loading })
+
+	if _, ok := a.Cache.Conversations["C2"]; ok || a.sidebar.has("C2") {
+		t.Error("the second workspace's channels ended up in the active one")
+	}
+	if _, ok := loading.cache.Conversations["C2"]; !ok || !loading.sidebar.has("C2") {
+		t.Error("the second workspace didn't get its own channels")
+	}
+
+	a.switchWorkspace(1, &cmds)
+	if a.InitialLoading || a.CurrentChannel != "C2" || !a.sidebar.has("C2") {
+		t.Errorf("switching to the loaded workspace: loading %v, channel %q", a.InitialLoading, a.CurrentChannel)
+	}
+}
@@ -0,0 +1,243 @@
+package channel
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/sahilm/fuzzy"
+	"github.com/slack-go/slack"
+)
+
+const maxSwitcherResults = 10
+
+type switcherResult struct {
+	title  string
+	id     string
+	userID string
+	score  int
+	latest string
+}
+
+func (a *app) openSwitcher() {
+	a.popup.popupType = PopupSwitcher
+	a.popup.input.SetHeight(1)
+	a.popup.input.ShowLineNumbers = false
+	a.popup.input.Placeholder = "Jump to a channel, DM or person..."
+	a.popup.input.SetWidth(50)
+	a.popup.input.Reset()
+	a.popup.isVisible = true
+	a.popup.input.Focus()
+
+	a.updateSwitcherResults()
+}
+
+func (a *app) switcherKeybinds(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "up", "ctrl+p":
+		if a.popup.selected > 0 {
+			a.popup.selected--
+		}
+	case "down", "ctrl+n":
+		if a.popup.selected < len(a.popup.results)-1 {
+			a.popup.selected++
+		}
+	case "enter", "alt+enter":
+		if len(a.popup.results) == 0 {
+			return a, nil
+		}
+		result := a.popup.results[a.popup.selected]
+
+		a.popup.input.Reset()
+		a.popup.input.Blur()
+		a.popup.isVisible = false
+
+		if result.id != "" {
+			return a, func() tea.Msg {
+				return core.ChannelSelectedMsg{Id: result.id}
+			}
+		}
+
+		go a.openDM(result.userID)
+	default:
+		a.popup.input, cmd = a.popup.input.Update(msg)
+		a.updateSwitcherResults()
+	}
+	return a, cmd
+}
+
+func (a *app) updateSwitcherResults() {
+	candidates := a.switcherCandidates()
+	query := strings.TrimLeft(strings.TrimSpace(a.popup.input.Value()), "#@")
+
+	var results []switcherResult
+	if query == "" {
+		results = candidates
+	} else {
+		titles := make([]string, len(candidates))
+		for i, candidate := range candidates {
+			titles[i] = candidate.title
+		}
+
+		for _, match := range fuzzy.Find(query, titles) {
+			result := candidates[match.Index]
+			result.score += match.Score
+			results = append(results, result)
+		}
+	}
+
+	slices.SortStableFunc(results, func(first, second switcherResult) int {
+		if first.score != second.score {
+			return second.score - first.score
+		}
+		if first.latest != second.latest {
+			return strings.Compare(second.latest, first.latest)
+		}
+		return strings.Compare(first.title, second.title)
+	})
+
+	if len(results) > maxSwitcherResults {
+		results = results[:maxSwitcherResults]
+	}
+
+	a.popup.results = results
+	a.popup.selected = 0
+}
+
+func (a *app) switcherCandidates() []switcherResult {
+	var candidates []switcherResult
+	usersWithDM := make(map[string]bool)
+
+	a.Mutex.RLock()
+	defer a.Mutex.RUnlock()
+
+	for _, conv := range a.Cache.Conversations {
+		var title string
+		if strings.HasPrefix(conv.ID, "D") {
+			if conv.User.ID == "" {
+				continue
+			}
+			usersWithDM[conv.User.ID] = true
+			title = conv.User.Name
+		} else {
+			title = conv.Name
+		}
+
+		if title == "" {
+			continue
+		}
+
+		candidates = append(candidates, switcherResult{
+			title:  title,
+			id:     conv.ID,
+			userID: conv.User.ID,
+			score:  activityScore(conv),
+			latest: conv.LatestMessage,
+		})
+	}
+
+	for _, user := range a.Cache.Users {
+		if usersWithDM[user.ID] || user.Name == "" || user.Name == "..." {
+			continue
+		}
+
+		candidates = append(candidates, switcherResult{
+			title:  user.Name,
+			userID: user.ID,
+		})
+	}
+
+	return candidates
+}
+
+func activityScore(conv *core.Conversation) int {
+	score := 0
+
+	if conv.IsMember {
+		score += 5
+	}
+
+	if conv.MentionCount > 0 {
+		score += 30
+	} else if conv.LastRead < conv.LatestMessage {
+		score += 15
+	}
+
+	if sec, err := strconv.ParseInt(strings.Split(conv.LatestMessage, ".")[0], 10, 64); err == nil {
+		age := time.Since(time.Unix(sec, 0))
+		switch {
+		case age < 24*time.Hour:
+			score += 10
+		case age < 7*24*time.Hour:
+			score += 5
+		}
+	}
+
+	return score
+}
+
+func (a *app) openDM(userID string) {
+	channel, _, _, err := a.Client.OpenConversation(&slack.OpenConversationParameters{
+		Users:    []string{userID},
+		ReturnIM: true,
+	})
+	if err != nil {
+		a.showErrorPopup(fmt.Sprintf("Error opening DM: %v", err))
+		return
+	}
+
+	a.MsgChan <- core.InsertDMInSidebarMsg{
+		DM: core.Conversation{
+			ID: channel.ID,
+			User: core.User{
+				ID:   userID,
+				Name: a.getUser(userID, true),
+			},
+			LastRead:      channel.LastRead,
+			LatestMessage: channel.LastRead,
+			IsMember:      true,
+		},
+	}
+	a.MsgChan <- core.ChannelSelectedMsg{Id: channel.ID}
+}
+
+func (p popup) switcherView() string {
+	var rows []string
+
+	for i, result := range p.results {
+		icon := "#"
+		if result.userID != "" {
+			icon = "@"
+		}
+
+		rowStyle := lg.NewStyle().Foreground(p.theme.Subtle).Background(p.theme.Background)
+		if i == p.selected {
+			rowStyle = rowStyle.Foreground(p.theme.Selected).Bold(true)
+		}
+
+		title := runewidth.Truncate(utils.Sanitize(result.title), p.input.Width()-4, "…")
+		if result.id == "" {
+			title += " (new DM)"
+		}
+
+		rows = append(rows, rowStyle.Width(p.input.Width()).Render(icon+" "+title))
+	}
+
+	if len(rows) == 0 {
+		rows = append(rows, lg.NewStyle().Foreground(p.theme.Muted).Background(p.theme.Background).Width(p.input.Width()).Render("No matches"))
+	}
+
+	list := lg.JoinVertical(lg.Left, rows...)
+	help := lg.NewStyle().Background(p.theme.Background).Foreground(p.theme.Subtle).Width(p.input.Width()).Render("\n↑↓/Select  Enter/Open  Esc/Cancel")
+
+	return lg.JoinVertical(lg.Left, p.input.View(), list, help)
+}
@@ -0,0 +1,284 @@
+package channel
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/utils"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	threadsItemID      = "threads"
+	maxThreadRows      = 50
+	maxParentsPerFetch = 10
+)
+
+type threadRow struct {
+	listRow
+	channelID string
+	threadTs  string
+}
+
+func threadKey(channelID, threadTs string) string {
+	return channelID + ":" + threadTs
+}
+
+func unreadThreads(cache *core.Cache) int {
+	count := 0
+	for _, thread := range cache.Threads {
+		if thread.LastRead < thread.LatestTs {
+			count++
+		}
+	}
+	return count
+}
+
+func (a *app) cacheFor(ws *workspace) *core.Cache {
+	if ws == a.workspaces[a.activeWorkspace] {
+		return a.Cache
+	}
+	return ws.cache
+}
+
+func (a *app) trackThread(ws *workspace, ev *api.MessageEvent) {
+	switch ev.SubType {
+	case "", "thread_broadcast", "file_share", "bot_message", "me_message":
+	default:
+		return
+	}
+	if ev.ThreadTimestamp == "" || ev.ThreadTimestamp == ev.Timestamp {
+		return
+	}
+
+	cache := a.cacheFor(ws)
+	if cache.Threads == nil {
+		cache.Threads = make(map[string]*core.ThreadInfo)
+	}
+
+	thread, ok := cache.Threads[threadKey(ev.Channel, ev.ThreadTimestamp)]
+	if !ok {
+		if ev.User != ws.user && ev.ParentUserID != ws.user && !utils.MentionsUser(ev.Text, ws.user) {
+			return
+		}
+
+		thread = &core.ThreadInfo{ChannelID: ev.Channel, ThreadTs: ev.ThreadTimestamp, ParentUser: ev.ParentUserID}
+		cache.Threads[threadKey(ev.Channel, ev.ThreadTimestamp)] = thread
+	}
+
+	if ev.Timestamp > thread.LatestTs {
+		thread.LatestTs = ev.Timestamp
+		thread.LatestUser = ev.User
+		thread.LatestText = ev.Text
+	}
+
+	active := ws == a.workspaces[a.activeWorkspace]
+	viewing := active && a.threadWindow.isOpen && a.CurrentChannel == ev.Channel && a.threadWindow.parentTs == ev.ThreadTimestamp
+	if ev.User == ws.user || viewing {
+		thread.LastRead = max(thread.LastRead, ev.Timestamp)
+	}
+
+	a.saveCache(cache)
+
+	if active {
+		a.rerenderSidebar()
+		if a.popup.isVisible && a.popup.popupType == PopupThreads {
+			a.updateThreadRows()
+		}
+	}
+}
+
+func (a *app) markThreadRead(channelID, threadTs string) {
+	thread, ok := a.Cache.Threads[threadKey(channelID, threadTs)]
+	if !ok || thread.LastRead >= thread.LatestTs {
+		return
+	}
+
+	thread.LastRead = thread.LatestTs
+	a.saveCache(a.Cache)
+	a.rerenderSidebar()
+}
+
+func (a *app) updateThreadParent(channelID, threadTs, user, text string) {
+	thread, ok := a.Cache.Threads[threadKey(channelID, threadTs)]
+	if !ok || (thread.ParentUser == user && thread.ParentText == text) {
+		return
+	}
+
+	thread.ParentUser = user
+	thread.ParentText = text
+	a.saveCache(a.Cache)
+
+	if a.popup.isVisible && a.popup.popupType == PopupThreads {
+		a.updateThreadRows()
+	}
+}
+
+func (a *app) mergeFoundThreads(found []core.ThreadInfo) {
+	if a.Cache.Threads == nil {
+		a.Cache.Threads = make(map[string]*core.ThreadInfo)
+	}
+
+	for _, info := range found {
+		key := threadKey(info.ChannelID, info.ThreadTs)
+
+		thread, ok := a.Cache.Threads[key]
+		if !ok {
+			info.LastRead = info.LatestTs
+			a.Cache.Threads[key] = &info
+			continue
+		}
+
+		if info.LatestTs > thread.LatestTs {
+			thread.LatestTs = info.LatestTs
+			thread.LatestUser = info.LatestUser
+			thread.LatestText = info.LatestText
+			if info.LatestUser == a.User {
+				thread.LastRead = info.LatestTs
+			}
+		}
+	}
+
+	a.saveCache(a.Cache)
+	a.rerenderSidebar()
+}
+
+func (a *app) sortedThreads() []*core.ThreadInfo {
+	threads := make([]*core.ThreadInfo, 0, len(a.Cache.Threads))
+	for _, thread := range a.Cache.Threads {
+		threads = append(threads, thread)
+	}
+
+	slices.SortFunc(threads, func(first, second *core.ThreadInfo) int {
+		return strings.Compare(second.LatestTs, first.LatestTs)
+	})
+
+	if len(threads) > maxThreadRows {
+		threads = threads[:maxThreadRows]
+	}
+	return threads
+}
+
+func (a *app) loadThreadParents(cmds *[]tea.Cmd) {
+	if a.Offline {
+		return
+	}
+
+	fetched := 0
+	for _, thread := range a.sortedThreads() {
+		if thread.ParentText != "" || fetched == maxParentsPerFetch {
+			continue
+		}
+
+		*cmds = append(*cmds, api.GetThreadParent(a.Client, thread.ChannelID, thread.ThreadTs))
+		fetched++
+	}
+}
+
+func (a *app) openThreads(cmds *[]tea.Cmd) {
+	a.popup.popupType = PopupThreads
+	a.popup.input.SetWidth(70)
+	a.popup.input.Blur()
+	a.popup.isVisible = true
+	a.popup.selected = 0
+
+	a.updateThreadRows()
+
+	if !a.Offline {
+		*cmds = append(*cmds, api.SearchThreads(a.Client, a.Cache.Workspace, a.User))
+		a.loadThreadParents(cmds)
+	}
+}
+
+func (a *app) threadsKeybinds(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k", "ctrl+p":
+		if a.popup.selected > 0 {
+			a.popup.selected--
+		}
+	case "down", "j", "ctrl+n":
+		if a.popup.selected < len(a.popup.threads)-1 {
+			a.popup.selected++
+		}
+	case "enter":
+		if len(a.popup.threads) == 0 {
+			return a, nil
+		}
+		row := a.popup.threads[a.popup.selected]
+
+		a.popup.isVisible = false
+
+		return a, func() tea.Msg {
+			return core.ChannelSelectedMsg{Id: row.channelID, Ts: row.threadTs, ThreadTs: row.threadTs}
+		}
+	}
+	return a, nil
+}
+
+func (a *app) updateThreadRows() {
+	userName := func(userID string) string { return a.getUser(userID, false) }
+	channelName := func(channelID string) string { return a.getChannel(channelID, false) }
+	snippet := func(text string) string {
+		plain := utils.PlainMrkdwn(text, userName, channelName, a.Cache.Emoji)
+		return strings.Join(strings.Fields(utils.Sanitize(plain)), " ")
+	}
+
+	var rows []threadRow
+	for _, thread := range a.sortedThreads() {
+		channel := "#" + channelName(thread.ChannelID)
+		if strings.HasPrefix(thread.ChannelID, "D") {
+			if conv, ok := a.Cache.Conversations[thread.ChannelID]; ok && conv.User.Name != "" {
+				channel = "@" + conv.User.Name
+			}
+		}
+
+		parent := "…"
+		if thread.ParentText != "" {
+			parent = snippet(thread.ParentText)
+			if thread.ParentUser != "" {
+				parent = userName(thread.ParentUser) + ": " + parent
+			}
+		}
+
+		var latest string
+		if thread.LatestTs != "" {
+			latest = userName(thread.LatestUser) + ": " + snippet(thread.LatestText)
+		}
+
+		rows = append(rows, threadRow{
+			listRow: listRow{
+				title:     utils.Sanitize(channel) + " · " + parent,
+				text:      "↳ " + latest,
+				timestamp: formatShortTime(thread.LatestTs),
+				unread:    thread.LastRead < thread.LatestTs,
+			},
+			channelID: thread.ChannelID,
+			threadTs:  thread.ThreadTs,
+		})
+	}
+
+	a.popup.threads = rows
+	a.popup.selected = min(a.popup.selected, max(0, len(rows)-1))
+}
+
+func formatShortTime(ts string) string {
+	sec, err := strconv.ParseInt(strings.Split(ts, ".")[0], 10, 64)
+	if err != nil {
+		return ""
+	}
+	return time.Unix(sec, 0).Format("Jan 2 15:04")
+}
+
+func (p popup) threadsView() string {
+	rows := make([]listRow, len(p.threads))
+	for i, row := range p.threads {
+		rows[i] = row.listRow
+	}
+	return p.listView("Threads", rows,
+		"No threads yet, they show up here once you start, reply in or get mentioned in one",
+		"↑↓/Select  Enter/Open  Esc/Close")
+}
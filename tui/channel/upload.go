@@ -0,0 +1,185 @@
+package channel
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+const maxPathMatches = 8
+
+type uploadState struct {
+	threadTs string
+	matches  []string
+	err      string
+}
+
+func (a *app) openUpload(threadTs string) {
+	a.popup.popupType = PopupUpload
+	a.popup.input.SetHeight(1)
+	a.popup.input.ShowLineNumbers = false
+	a.popup.input.Placeholder = "Path of the file to upload..."
+	a.popup.input.SetWidth(60)
+	a.popup.input.Reset()
+	a.popup.isVisible = true
+	a.popup.input.Focus()
+
+	a.popup.upload = uploadState{threadTs: threadTs}
+}
+
+func (a *app) uploadKeybinds(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "tab":
+		completed, matches := completePath(a.popup.input.Value())
+		a.popup.input.SetValue(completed)
+		a.popup.input.CursorEnd()
+		a.popup.upload.matches = matches
+		a.popup.upload.err = ""
+	case "enter", "alt+enter":
+		path := utils.ExpandHome(strings.TrimSpace(a.popup.input.Value()))
+		if path == "" {
+			return a, nil
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			a.popup.upload.err = err.Error()
+			return a, nil
+		}
+		if info.IsDir() {
+			a.popup.upload.err = fmt.Sprintf("%v is a directory", path)
+			return a, nil
+		}
+
+		threadTs := a.popup.upload.threadTs
+		input, completion := &a.input, &a.completion
+		if threadTs != "" {
+			input, completion = &a.threadWindow.input, &a.threadWindow.completion
+		}
+		comment := completion.apply(input.Value())
+		input.Reset()
+
+		a.popup.input.Reset()
+		a.popup.input.Blur()
+		a.popup.isVisible = false
+
+		go a.uploadFile(a.CurrentChannel, threadTs, path, comment)
+	default:
+		a.popup.input, cmd = a.popup.input.Update(msg)
+		a.popup.upload.matches = nil
+		a.popup.upload.err = ""
+	}
+	return a, cmd
+}
+
+func (a *app) uploadFile(channelID, threadTs, path, comment string) {
+	if a.Offline {
+		a.showErrorPopup("Can't upload files in offline mode")
+		return
+	}
+
+	name := filepath.Base(path)
+	a.MsgChan <- core.UploadProgressMsg{Name: name}
+
+	_, err := api.UploadFile(a.Client, channelID, threadTs, path, a.resolveMentions(comment), func(sent, total int64) {
+		a.MsgChan <- core.UploadProgressMsg{Name: name, Sent: sent, Total: total}
+	})
+	a.MsgChan <- core.UploadFinishedMsg{Name: name, Err: err}
+}
+
+func (a *app) uploadIndicator() string {
+	if a.uploading.Name == "" {
+		return ""
+	}
+
+	name := runewidth.Truncate(utils.Sanitize(a.uploading.Name), 30, "…")
+	if a.uploading.Total == 0 {
+		return " · uploading " + name
+	}
+	return fmt.Sprintf(" · uploading %v %d%%", name, a.uploading.Sent*100/a.uploading.Total)
+}
+
+func completePath(value string) (string, []string) {
+	dir, prefix := filepath.Split(value)
+
+	searchDir := utils.ExpandHome(dir)
+	if searchDir == "" {
+		searchDir = "."
+	}
+
+	entries, err := os.ReadDir(searchDir)
+	if err != nil {
+		return value, nil
+	}
+
+	var matches []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, prefix) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".")) {
+			continue
+		}
+
+		if info, err := os.Stat(filepath.Join(searchDir, name)); err == nil && info.IsDir() {
+			name += string(filepath.Separator)
+		}
+		matches = append(matches, name)
+	}
+
+	if len(matches) == 0 {
+		return value, nil
+	}
+
+	common := []rune(matches[0])
+	for _, match := range matches[1:] {
+		n := 0
+		for _, r := range match {
+			if n == len(common) || common[n] != r {
+				break
+			}
+			n++
+		}
+		common = common[:n]
+	}
+
+	if len(matches) == 1 {
+		return dir + string(common), nil
+	}
+	return dir + string(common), matches
+}
+
+func (p popup) uploadView() string {
+	width := p.input.Width()
+	mutedStyle := lg.NewStyle().Background(p.theme.Background).Foreground(p.theme.Muted).Width(width)
+
+	var lines []string
+	for i, match := range p.upload.matches {
+		if i == maxPathMatches {
+			lines = append(lines, mutedStyle.Render(fmt.Sprintf("… %d more", len(p.upload.matches)-maxPathMatches)))
+			break
+		}
+		lines = append(lines, mutedStyle.Render(runewidth.Truncate(utils.Sanitize(match), width, "…")))
+	}
+
+	if p.upload.err != "" {
+		lines = append(lines, lg.NewStyle().Background(p.theme.Background).Foreground(p.theme.Primary).Width(width).Render(utils.Sanitize(p.upload.err)))
+	}
+
+	target := "the channel"
+	if p.upload.threadTs != "" {
+		target = "the thread"
+	}
+	info := mutedStyle.Render("Uploads to " + target + " with the text from the input box")
+	help := lg.NewStyle().Background(p.theme.Background).Foreground(p.theme.Subtle).Width(width).Render("\nTab/Complete  Enter/Upload  Esc/Cancel")
+
+	return lg.JoinVertical(lg.Left, append([]string{p.input.View()}, append(lines, info, help)...)...)
+}
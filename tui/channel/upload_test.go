@@ -0,0 +1,47 @@
+package channel
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCompletePath(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"café.txt", "cafè.txt", "notes.md", "notebook.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "photos"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		value   string
+		want    string
+		matches int
+	}{
+		{"caf", "caf", 2},
+		{"note", "note", 2},
+		{"notes", "notes.md", 0},
+		{"pho", "photos" + string(filepath.Separator), 0},
+		{"missing", "missing", 0},
+	}
+
+	for _, test := range tests {
+		got, matches := completePath(filepath.Join(dir, test.value))
+		want := filepath.Join(dir, test.want)
+		if test.want[len(test.want)-1] == filepath.Separator {
+			want += string(filepath.Separator)
+		}
+
+		if got != want || len(matches) != test.matches {
+			t.Errorf("completePath(%q) = %q, %v, want %q with %d matches", test.value, got, matches, want, test.matches)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("completePath(%q) = %q, cut a rune in half", test.value, got)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package channel
 
 import (
 	"fmt"
-	"regexp"
 	"slices"
 	"strings"
 	"time"
@@ -12,7 +11,9 @@ import (
 
 	"github.com/Jan-Kur/HackCLI/api"
 	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/images"
 	"github.com/Jan-Kur/HackCLI/tui/styles"
+	"github.com/Jan-Kur/HackCLI/utils"
 	tea "github.com/charmbracelet/bubbletea"
 	lg "github.com/charmbracelet/lipgloss"
 	"github.com/slack-go/slack"
@@ -103,6 +104,316 @@ func (a *app) insertMessage(newMessage core.Message, chat *chat) {
 	chat.messages[idx] = newMessage
 }
 
+func (a *app) prependMessages(cmds *[]tea.Cmd, messages []core.Message) {
+	var selectedTs string
+	if a.chat.selectedMessage >= 0 && a.chat.selectedMessage < len(a.chat.messages) {
+		selectedTs = a.chat.messages[a.chat.selectedMessage].Ts
+	}
+
+	previousLines := a.chat.viewport.TotalLineCount()
+	previousOffset := a.chat.viewport.YOffset
+
+	for _, mes := range messages {
+		a.insertMessage(mes, &a.chat)
+	}
+
+	for i, mes := range a.chat.messages {
+		if mes.Ts == selectedTs {
+			a.chat.selectedMessage = i
+			break
+		}
+	}
+
+	a.renderChat(cmds, &a.chat, false)
+	a.chat.viewport.SetYOffset(previousOffset + a.chat.viewport.TotalLineCount() - previousLines)
+}
+
+func (a *app) appendMessages(cmds *[]tea.Cmd, messages []core.Message) {
+	goToBottom := a.chat.viewport.AtBottom()
+	wasLastSelected := a.chat.selectedMessage == len(a.chat.messages)-1
+
+	var selectedTs string
+	if a.chat.selectedMessage >= 0 && a.chat.selectedMessage < len(a.chat.messages) {
+		selectedTs = a.chat.messages[a.chat.selectedMessage].Ts
+	}
+
+	for _, mes := range messages {
+		a.insertMessage(mes, &a.chat)
+	}
+
+	if wasLastSelected {
+		a.chat.selectedMessage = len(a.chat.messages) - 1
+	} else {
+		for i, mes := range a.chat.messages {
+			if mes.Ts == selectedTs {
+				a.chat.selectedMessage = i
+				break
+			}
+		}
+	}
+
+	a.renderChat(cmds, &a.chat, false)
+	if goToBottom {
+		a.chat.viewport.GotoBottom()
+	}
+}
+
+func (a *app) loadChannelHistory(cmds *[]tea.Cmd) {
+	stored := a.store.Messages(a.CurrentChannel, "", historyPageSize)
+	if len(stored) > 0 {
+		a.chat.messages = stored
+		a.chat.hasMore = len(stored) == historyPageSize
+		a.chat.selectedMessage = len(a.chat.messages) - 1
+		a.renderChat(cmds, &a.chat, false)
+		if a.chat.viewport.Height > 0 {
+			a.chat.viewport.GotoBottom()
+		}
+	}
+
+	if !a.Offline {
+		*cmds = append(*cmds, api.GetChannelHistory(a.Client, a.CurrentChannel))
+	}
+}
+
+func mergeHistory(current, fetched []core.Message, hasMore bool) []core.Message {
+	if len(fetched) == 0 {
+		return current
+	}
+
+	oldest, newest := fetched[0].Ts, fetched[len(fetched)-1].Ts
+
+	fetchedTs := make(map[string]bool)
+	for _, mes := range fetched {
+		fetchedTs[mes.Ts] = true
+	}
+
+	overlaps := !hasMore || slices.ContainsFunc(current, func(mes core.Message) bool {
+		return fetchedTs[mes.Ts]
+	})
+
+	merged := slices.Clone(fetched)
+	for _, mes := range current {
+		switch {
+		case fetchedTs[mes.Ts]:
+		case mes.Ts > newest:
+			merged = append(merged, mes)
+		case mes.Ts < oldest && overlaps:
+			merged = append(merged, mes)
+		}
+	}
+
+	slices.SortFunc(merged, sortingMessagesAlgorithm)
+	return merged
+}
+
+func mergeReplies(current, fetched []core.Message) []core.Message {
+	merged := slices.Clone(current)
+	for _, mes := range fetched {
+		if !slices.ContainsFunc(merged, func(existing core.Message) bool { return existing.Ts == mes.Ts }) {
+			merged = append(merged, mes)
+		}
+	}
+
+	slices.SortFunc(merged, sortingMessagesAlgorithm)
+	return merged
+}
+
+func (a *app) markChannelRead(latestTs string) {
+	channelID := a.CurrentChannel
+
+	if conv, ok := a.Cache.Conversations[channelID]; ok {
+		conv.LastRead = latestTs
+		if latestTs > conv.LatestMessage {
+			conv.LatestMessage = latestTs
+		}
+		conv.UnreadCount = 0
+		conv.MentionCount = 0
+	}
+
+	client := a.Client
+	go func() {
+		if err := client.MarkConversation(channelID, latestTs); err != nil {
+			a.showErrorPopup(fmt.Sprintf("Error marking conversation as read: %v", err))
+		}
+	}()
+}
+
+func (a *app) backfill(cmds *[]tea.Cmd) {
+	if a.CurrentChannel != "" {
+		if last := len(a.chat.messages) - 1; last >= 0 {
+			*cmds = append(*cmds, api.GetNewerHistory(a.Client, a.CurrentChannel, a.chat.messages[last].Ts))
+		} else {
+			*cmds = append(*cmds, api.GetChannelHistory(a.Client, a.CurrentChannel))
+		}
+	}
+
+	if a.threadWindow.isOpen {
+		*cmds = append(*cmds, api.GetThread(a.Client, a.CurrentChannel, a.threadWindow.parentTs, ""))
+	}
+
+	var conversationIDs []string
+	for _, item := range a.sidebar.items {
+		if !item.isHeader && !item.isView() && item.id != a.CurrentChannel {
+			conversationIDs = append(conversationIDs, item.id)
+		}
+	}
+
+	go a.refreshUnreadState(a.workspaces[a.activeWorkspace], conversationIDs)
+}
+
+func (a *app) trackUnread(ev *api.MessageEvent) {
+	conv, ok := a.Cache.Conversations[ev.Channel]
+	if !ok || !countsAsUnread(ev) {
+		return
+	}
+
+	if ev.Timestamp > conv.LatestMessage {
+		conv.LatestMessage = ev.Timestamp
+	}
+
+	if ev.Channel == a.CurrentChannel || ev.User == a.User {
+		conv.LastRead = ev.Timestamp
+		conv.UnreadCount = 0
+		conv.MentionCount = 0
+	} else {
+		conv.UnreadCount++
+		if strings.HasPrefix(ev.Channel, "D") || a.mentionsMe(ev.Text) {
+			conv.MentionCount++
+		}
+	}
+
+	a.saveCache(a.Cache)
+	a.rerenderSidebar()
+}
+
+func countsAsUnread(ev *api.MessageEvent) bool {
+	switch ev.SubType {
+	case "", "thread_broadcast", "file_share", "bot_message", "me_message":
+	default:
+		return false
+	}
+
+	return ev.ThreadTimestamp == "" || ev.Timestamp == ev.ThreadTimestamp || ev.SubType == "thread_broadcast"
+}
+
+func (a *app) mentionsMe(text string) bool {
+	return utils.MentionsUser(text, a.User) || utils.MentionsEveryone(text)
+}
+
+func (a *app) loadOlderMessages(cmds *[]tea.Cmd) {
+	if a.chat.loadingOlder || !a.chat.hasMore || len(a.chat.messages) == 0 {
+		return
+	}
+
+	a.chat.loadingOlder = true
+	if a.Offline {
+		*cmds = append(*cmds, a.getStoredHistory(a.CurrentChannel, a.chat.messages[0].Ts))
+		return
+	}
+	*cmds = append(*cmds, api.GetOlderHistory(a.Client, a.CurrentChannel, a.chat.messages[0].Ts))
+}
+
+func (a *app) getStoredHistory(channelID string, before string) tea.Cmd {
+	return func() tea.Msg {
+		messages := a.store.Messages(channelID, before, historyPageSize)
+
+		return core.HistoryLoadedMsg{
+			ChannelID: channelID,
+			Messages:  messages,
+			Older:     true,
+			HasMore:   len(messages) == historyPageSize,
+		}
+	}
+}
+
+func (a *app) getStoredHistoryAround(channelID, ts string) tea.Cmd {
+	return func() tea.Msg {
+		messages := a.store.Messages(channelID, "", historyPageSize)
+		hasMore := len(messages) == historyPageSize
+		for hasMore && messages[0].Ts > ts {
+			older := a.store.Messages(channelID, messages[0].Ts, historyPageSize)
+			hasMore = len(older) == historyPageSize
+			messages = append(older, messages...)
+		}
+
+		return core.HistoryLoadedMsg{
+			ChannelID: channelID,
+			Messages:  messages,
+			HasMore:   hasMore,
+			FocusTs:   ts,
+		}
+	}
+}
+
+func (a *app) loadNewerMessages(cmds *[]tea.Cmd) {
+	if a.chat.loadingNewer || !a.chat.hasNewer || len(a.chat.messages) == 0 {
+		return
+	}
+
+	a.chat.loadingNewer = true
+	*cmds = append(*cmds, api.GetNewerHistory(a.Client, a.CurrentChannel, a.chat.messages[len(a.chat.messages)-1].Ts))
+}
+
+func (a *app) loadMoreReplies(cmds *[]tea.Cmd) {
+	if a.Offline || a.threadWindow.loadingMore || !a.threadWindow.hasMore {
+		return
+	}
+
+	a.threadWindow.loadingMore = true
+	*cmds = append(*cmds, api.GetThread(a.Client, a.CurrentChannel, a.threadWindow.parentTs, a.threadWindow.cursor))
+}
+
+func (a *app) openThread(cmds *[]tea.Cmd, parentTs, focusTs string) {
+	a.threadWindow.isOpen = true
+	a.threadWindow.parentTs = parentTs
+	a.threadWindow.focusTs = focusTs
+	a.threadWindow.cursor = ""
+	a.threadWindow.hasMore = false
+	a.threadWindow.loadingMore = false
+	a.threadWindow.broadcast = false
+	a.markThreadRead(a.CurrentChannel, parentTs)
+	a.threadWindow.chat.messages = a.store.Replies(a.CurrentChannel, parentTs)
+	a.threadWindow.chat.selectedMessage = len(a.threadWindow.chat.messages) - 1
+	for i, mes := range a.threadWindow.chat.messages {
+		if mes.Ts == focusTs {
+			a.threadWindow.chat.selectedMessage = i
+		}
+	}
+
+	if !a.Offline {
+		*cmds = append(*cmds, api.GetThread(a.Client, a.CurrentChannel, parentTs, ""))
+	}
+	*cmds = append(*cmds, func() tea.Msg {
+		return tea.WindowSizeMsg{Width: a.width, Height: a.height}
+	})
+}
+
+func isScrollDown(msg tea.Msg) bool {
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		return msg.Button == tea.MouseButtonWheelDown
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "pgdown", "ctrl+d", "f", " ":
+			return true
+		}
+	}
+	return false
+}
+
+func isScrollUp(msg tea.Msg) bool {
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		return msg.Button == tea.MouseButtonWheelUp
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "pgup", "ctrl+u", "b", "u":
+			return true
+		}
+	}
+	return false
+}
+
 func (a *app) chatKeybinds(key string, cmds *[]tea.Cmd, isThread bool, chat *chat) bool {
 	switch key {
 	case "up":
@@ -114,6 +425,8 @@ func (a *app) chatKeybinds(key string, cmds *[]tea.Cmd, isThread bool, chat *cha
 			chat.selectedMessage = nextIndex
 			chat.viewport.ScrollUp(lines)
 			a.updateMessage(cmds, chat, isThread, chat.selectedMessage, chat.selectedMessage+1)
+		} else if !isThread {
+			a.loadOlderMessages(cmds)
 		}
 	case "down":
 		nextIndex := chat.selectedMessage + 1
@@ -124,45 +437,51 @@ func (a *app) chatKeybinds(key string, cmds *[]tea.Cmd, isThread bool, chat *cha
 
 			chat.viewport.ScrollDown(lines)
 			a.updateMessage(cmds, chat, isThread, chat.selectedMessage, chat.selectedMessage-1)
+		} else if !isThread {
+			a.loadNewerMessages(cmds)
+		} else {
+			a.loadMoreReplies(cmds)
 		}
 	case "j":
 		nextIndex := chat.selectedMessage - 1
 		if nextIndex != -1 {
 			chat.selectedMessage = nextIndex
 			a.updateMessage(cmds, chat, isThread, chat.selectedMessage, chat.selectedMessage+1)
+		} else if !isThread {
+			a.loadOlderMessages(cmds)
 		}
 	case "k":
 		nextIndex := chat.selectedMessage + 1
 		if nextIndex != len(chat.messages) {
 			chat.selectedMessage = nextIndex
 			a.updateMessage(cmds, chat, isThread, chat.selectedMessage, chat.selectedMessage-1)
+		} else if !isThread {
+			a.loadNewerMessages(cmds)
+		} else {
+			a.loadMoreReplies(cmds)
 		}
 	case "enter":
 		if !isThread {
 			mes := &chat.messages[chat.selectedMessage]
-			if a.threadWindow.parentTs == mes.Ts {
+			parentTs, focusTs := mes.Ts, ""
+			if mes.SubType == "thread_broadcast" && mes.ThreadId != "" {
+				parentTs, focusTs = mes.ThreadId, mes.Ts
+			}
+
+			if a.threadWindow.parentTs == parentTs {
 				a.threadWindow.isOpen = false
 				a.threadWindow.parentTs = ""
 				a.MsgChan <- tea.WindowSizeMsg{Width: a.width, Height: a.height}
 			} else {
-				a.threadWindow.isOpen = true
-				a.threadWindow.parentTs = mes.Ts
-				*cmds = append(*cmds, api.GetThread(a.Client, a.CurrentChannel, mes.Ts))
-				a.MsgChan <- tea.WindowSizeMsg{Width: a.width, Height: a.height}
+				a.openThread(cmds, parentTs, focusTs)
 			}
 		}
 	case "r":
-		mes := chat.messages[chat.selectedMessage]
-
-		a.popup.popupType = PopupReaction
-		a.popup.targetMes = mes
-		a.popup.input.SetHeight(1)
-		a.popup.input.ShowLineNumbers = false
-		a.popup.input.Placeholder = "thumbsup"
-		a.popup.input.SetWidth(50)
-		a.popup.input.Reset()
-		a.popup.isVisible = true
-		a.popup.input.Focus()
+		a.openEmojiPicker(chat.messages[chat.selectedMessage])
+	case "s":
+		a.openFiles(chat.messages[chat.selectedMessage])
+	case "a":
+		a.openActions(chat.messages[chat.selectedMessage])
 	case "d":
 		mes := &chat.messages[chat.selectedMessage]
 		if mes.User == a.User {
@@ -219,13 +538,14 @@ func (a *app) getHistoryUsersCmd() tea.Cmd {
 	}
 	a.Mutex.RUnlock()
 
+	client, domain := a.Client, a.Cache.Workspace
 	for userID := range users {
 		cmd := func() tea.Msg {
-			user, err := api.GetUserInfo(a.Client, userID)
+			user, err := api.GetUserInfo(client, userID)
 			if err != nil {
 				return nil
 			}
-			return core.UserInfoLoadedMsg{User: user, IsHistory: true}
+			return core.UserInfoLoadedMsg{Workspace: domain, User: user, IsHistory: true}
 		}
 		cmds = append(cmds, cmd)
 	}
@@ -237,14 +557,48 @@ func (a *app) styleSidebar() string {
 	if a.focused == FocusSidebar {
 		style = style.BorderForeground(a.theme.Selected)
 	}
-	return style.Render(a.sidebar.View(a.theme, a.Cache))
+	return style.Render(a.sidebar.View(a.theme, a.Cache, a.Config.Notifications.Muted))
 }
 
 func (a *app) styleMainChat() string {
+	label := a.workspaceLabel() + a.CurrentChannel + a.connectionIndicator() + a.uploadIndicator() + a.downloadIndicator() + a.doNotDisturbIndicator() + a.backgroundUnread()
+
 	if a.focused == FocusChat {
-		return focusedChatStyle.Render(a.CurrentChannel, a.chat.viewport.View(), a.chat.chatWidth-2)
+		return focusedChatStyle.Render(label, images.ClipSixel(a.chat.viewport.View()), a.chat.chatWidth-2)
 	}
-	return chatStyle.Render(a.CurrentChannel, a.chat.viewport.View(), a.chat.chatWidth-2)
+	return chatStyle.Render(label, images.ClipSixel(a.chat.viewport.View()), a.chat.chatWidth-2)
+}
+
+func (a *app) loadingStatus() string {
+	progress := a.loadingProgress
+	if progress.Total == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("\nLoading %v %d/%d", progress.Stage, progress.Done, progress.Total)
+}
+
+func retryTick(retryAt time.Time) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return core.RetryTickMsg{RetryAt: retryAt}
+	})
+}
+
+func (a *app) connectionIndicator() string {
+	if a.Offline {
+		return " ✕ offline mode"
+	}
+
+	switch a.connection {
+	case core.Connecting:
+		return " ⟳ connecting"
+	case core.Disconnected:
+		if remaining := time.Until(a.retryAt); remaining > 0 {
+			return fmt.Sprintf(" ✕ offline, retrying in %v", (remaining + time.Second - 1).Truncate(time.Second))
+		}
+		return " ✕ offline"
+	}
+	return ""
 }
 
 func (a *app) styleMainInput() string {
@@ -260,7 +614,7 @@ func (a *app) styleThreadChat() string {
 	if a.focused == FocusThreadChat {
 		style = style.BorderForeground(a.theme.Selected)
 	}
-	return style.Render(a.threadWindow.chat.viewport.View())
+	return style.Render(images.ClipSixel(a.threadWindow.chat.viewport.View()))
 }
 
 func (a *app) styleThreadInput() string {
@@ -268,7 +622,13 @@ func (a *app) styleThreadInput() string {
 	if a.focused == FocusThreadInput {
 		style = style.BorderForeground(a.theme.Selected)
 	}
-	return style.Render(a.threadWindow.input.View())
+	label := "☐ also send to channel"
+	if a.threadWindow.broadcast {
+		label = "☑ also send to channel"
+	}
+
+	input := a.threadWindow.input.View()
+	return styles.BoxWithLabel{BoxStyle: style, LabelStyle: threadLabelStyle}.Render(label, input, lg.Width(input))
 }
 
 func (a *app) renderLine() string {
@@ -300,6 +660,8 @@ func InitializeStyles(theme styles.Theme) {
 
 	threadInputStyle = lg.NewStyle().Border(lg.RoundedBorder(), true).BorderForeground(theme.Border).
 		Foreground(theme.Text).Background(theme.Background).BorderBackground(theme.Background)
+
+	threadLabelStyle = lg.NewStyle().Foreground(theme.Muted).Background(theme.Background)
 }
 
 func (a *app) showErrorPopup(errorMessage string) {
@@ -309,46 +671,6 @@ func (a *app) showErrorPopup(errorMessage string) {
 	a.MsgChan <- core.WaitMsg{Msg: core.CloseErrorPopupMsg{}, Duration: 3 * time.Second}
 }
 
-func (a *app) findMentionsInMessageContent(text string) string {
-	finalText := text
-
-	userMentionRegex, _ := regexp.Compile(`<@(U[A-Z0-9]{8,11})>`)
-	channelMentionRegex, _ := regexp.Compile(`<#([CG][A-Z0-9]{8,11})\|?>`)
-	linkRegex, _ := regexp.Compile(`<((http|https)://[A-Za-z0-9\-._~:/?#@!$&'()*+,;=%]+)\|?([A-Za-z0-9 \-._~:/?#@!$&'()*+,;=%]*)>`)
-
-	for {
-		userID := userMentionRegex.FindStringSubmatch(finalText)
-		if userID != nil {
-			before, after, _ := strings.Cut(finalText, userID[0])
-			finalText = before + a.styleUserMention(userID[1]) + after
-		} else {
-			break
-		}
-	}
-
-	for {
-		channelID := channelMentionRegex.FindStringSubmatch(finalText)
-		if channelID != nil {
-			before, after, _ := strings.Cut(finalText, channelID[0])
-			finalText = before + a.styleChannelMention(channelID[1]) + after
-		} else {
-			break
-		}
-	}
-
-	for {
-		link := linkRegex.FindStringSubmatch(finalText)
-		if link != nil {
-			before, after, _ := strings.Cut(finalText, link[0])
-			finalText = before + a.styleLink(link[1]) + after
-		} else {
-			break
-		}
-	}
-
-	return finalText
-}
-
 func (a *app) getUser(userID string, instant bool) string {
 	a.Mutex.RLock()
 	user, ok := a.Cache.Users[userID]
@@ -372,27 +694,23 @@ func (a *app) getUser(userID string, instant bool) string {
 			a.showErrorPopup(fmt.Sprintf("Error getting user info: %v", err))
 			return "..."
 		} else {
-			username := user.Profile.DisplayName
-			if username == "" {
-				if firstName := user.Profile.FirstName; firstName != "" {
-					username = firstName
-				} else {
-					username = user.RealName
-				}
-			}
+			username := api.DisplayName(user)
+			a.Mutex.Lock()
 			a.Cache.Users[userID] = &core.User{ID: userID, Name: sanitize(username)}
+			a.Mutex.Unlock()
 
-			go api.SaveCache(*a.Cache)
+			a.saveCache(a.Cache)
 
 			return sanitize(username)
 		}
 	} else {
+		client, domain := a.Client, a.Cache.Workspace
 		go func() {
-			user, err := api.GetUserInfo(a.Client, userID)
+			user, err := api.GetUserInfo(client, userID)
 			if err != nil {
 				a.showErrorPopup(fmt.Sprintf("Error getting user info: %v", err))
 			} else {
-				a.MsgChan <- core.UserInfoLoadedMsg{User: user, IsHistory: false}
+				a.MsgChan <- core.UserInfoLoadedMsg{Workspace: domain, User: user, IsHistory: false}
 			}
 		}()
 		return "..."
@@ -400,7 +718,10 @@ func (a *app) getUser(userID string, instant bool) string {
 }
 
 func (a *app) getChannel(channelID string, instant bool) string {
-	if ch, ok := a.Cache.Conversations[channelID]; ok {
+	a.Mutex.RLock()
+	ch, ok := a.Cache.Conversations[channelID]
+	a.Mutex.RUnlock()
+	if ok {
 		return ch.Name
 	}
 	if instant {
@@ -413,20 +734,23 @@ func (a *app) getChannel(channelID string, instant bool) string {
 			a.showErrorPopup(fmt.Sprintf("Error getting channel info: %v", err))
 			return "..."
 		} else {
+			a.Mutex.Lock()
 			conv, ok := a.Cache.Conversations[channelID]
 			if !ok {
 				conv = &core.Conversation{ID: channelID}
 				a.Cache.Conversations[channelID] = conv
 			}
 			conv.Name = channel.Name
+			a.Mutex.Unlock()
 
-			go api.SaveCache(*a.Cache)
+			a.saveCache(a.Cache)
 
 			return channel.Name
 		}
 	} else {
+		client, domain := a.Client, a.Cache.Workspace
 		go func() {
-			channel, err := a.Client.GetConversationInfo(&slack.GetConversationInfoInput{
+			channel, err := client.GetConversationInfo(&slack.GetConversationInfoInput{
 				ChannelID:         channelID,
 				IncludeLocale:     false,
 				IncludeNumMembers: false,
@@ -434,14 +758,16 @@ func (a *app) getChannel(channelID string, instant bool) string {
 			if err != nil {
 				a.showErrorPopup(fmt.Sprintf("Error getting channel info: %v", err))
 			} else {
-				latest, err := api.GetLatestMessage(a.Client, channelID)
+				latest, err := api.GetLatestMessage(client, channelID)
 				if err != nil {
 					a.MsgChan <- core.ChannelInfoLoadedMsg{
+						Workspace: domain,
 						Channel:   channel,
 						LatestMes: "",
 					}
 				} else {
 					a.MsgChan <- core.ChannelInfoLoadedMsg{
+						Workspace: domain,
 						Channel:   channel,
 						LatestMes: latest.Timestamp,
 					}
@@ -455,6 +781,8 @@ func (a *app) getChannel(channelID string, instant bool) string {
 func (a *app) buildSidebar(channels []core.Conversation, dms []core.Conversation) []sidebarItem {
 	var items []sidebarItem
 
+	items = append(items, sidebarItem{id: threadsItemID, title: "Threads"})
+	items = append(items, sidebarItem{id: activityItemID, title: "Activity"})
 	items = append(items, sidebarItem{id: "", title: "════ CHANNELS " + strings.Repeat("═", 100), isHeader: true})
 
 	for _, ch := range channels {
@@ -471,7 +799,7 @@ func (a *app) buildSidebar(channels []core.Conversation, dms []core.Conversation
 }
 
 func sanitize(username string) string {
-	username = norm.NFKC.String(username)
+	username = norm.NFKC.String(utils.Sanitize(username))
 	var b strings.Builder
 	for _, r := range username {
 		if r == '\uFEFF' || r == '\u200B' || r == '\u2060' {
@@ -489,71 +817,44 @@ func sanitize(username string) string {
 }
 
 func (a *app) getMentionID(mention string) string {
-	if strings.HasPrefix(mention, "@") {
-		username := strings.TrimPrefix(mention, "@")
-		var userID string
-		for _, user := range a.Cache.Users {
-			if user.Name == username {
-				userID = user.ID
-				break
-			}
-		}
-		if userID != "" {
-			return fmt.Sprintf("<@%v>", userID)
-		} else {
-			return mention
-		}
-	} else {
-		channelName := strings.TrimPrefix(mention, "#")
-		var channelID string
-		for _, channel := range a.Cache.Conversations {
-			if channel.Name == channelName {
-				channelID = channel.ID
-				break
-			}
-		}
-		if channelID != "" {
-			return fmt.Sprintf("<#%v>", channelID)
-		} else {
-			return mention
-		}
-	}
+	return api.MentionID(a.Cache, mention)
+}
+
+func (a *app) resolveMentions(content string) string {
+	return api.ResolveMentions(a.Cache, content)
 }
 
 func (a *app) SendMessage(content string) {
+	if a.Offline {
+		a.showErrorPopup("Can't send messages in offline mode")
+		return
+	}
+
 	var err error
 	api.WithRetry(func() error {
-		finalContent := content
-
-		mentionRegex, _ := regexp.Compile(`(@[^\s#]+|#[a-z0-9_-]+)`)
-
-		finalContent = mentionRegex.ReplaceAllStringFunc(content, func(mention string) string {
-			trimmed := strings.TrimRight(mention, ".,!?;:)}]")
-			trailing := strings.TrimPrefix(mention, trimmed)
-
-			return a.getMentionID(trimmed) + trailing
-		})
+		finalContent := a.resolveMentions(content)
 
 		_, _, _, err = a.Client.SendMessage(a.CurrentChannel, slack.MsgOptionText(finalContent, false))
 		return err
 	})
 }
 
-func (a *app) SendReply(content string, parentTs string) {
+func (a *app) SendReply(content string, parentTs string, broadcast bool) {
+	if a.Offline {
+		a.showErrorPopup("Can't send messages in offline mode")
+		return
+	}
+
 	var err error
 	api.WithRetry(func() error {
-		finalContent := content
-
-		mentionRegex, _ := regexp.Compile(`(@[^\s#]+|#[a-z0-9_-]+)`)
+		finalContent := a.resolveMentions(content)
 
-		finalContent = mentionRegex.ReplaceAllStringFunc(content, func(mention string) string {
-			trimmed := strings.TrimRight(mention, ".,!?;:)}]")
-			trailing := strings.TrimPrefix(mention, trimmed)
-
-			return a.getMentionID(trimmed) + trailing
-		})
+		options := []slack.MsgOption{slack.MsgOptionText(finalContent, false), slack.MsgOptionTS(parentTs)}
+		if broadcast {
+			options = append(options, slack.MsgOptionBroadcast())
+		}
 
-		_, _, _, err = a.Client.SendMessage(a.CurrentChannel, slack.MsgOptionText(finalContent, false), slack.MsgOptionTS(parentTs))
+		_, _, _, err = a.Client.SendMessage(a.CurrentChannel, options...)
 		return err
 	})
 }
@@ -0,0 +1,263 @@
+package channel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Jan-Kur/HackCLI/api"
+	"github.com/Jan-Kur/HackCLI/core"
+	"github.com/Jan-Kur/HackCLI/utils"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type workspace struct {
+	config         core.Workspace
+	client         core.SlackClient
+	events         core.EventSource
+	cache          *core.Cache
+	store          *api.Store
+	user           string
+	loading        bool
+	loaded         bool
+	currentChannel string
+	sidebar        sidebar
+	connection     core.ConnectionState
+	retryAt        time.Time
+	unread         int
+	mentions       int
+}
+
+func openWorkspace(config core.Workspace, offline bool) (*workspace, error) {
+	cache, firstRun := api.LoadCacheOrEmpty(config.Domain)
+	if offline && firstRun {
+		return nil, fmt.Errorf("nothing stored for %v yet, run HackCLI online at least once", config.Domain)
+	}
+
+	client := api.NewClient(config)
+
+	if !offline {
+		user, err := client.AuthTest()
+		if err != nil {
+			return nil, fmt.Errorf("couldn't connect to %v: %w", config.Domain, err)
+		}
+		if cache.UserID != user.UserID {
+			cache.UserID = user.UserID
+			api.SaveCache(*cache)
+		}
+	}
+
+	store, err := api.OpenStore(config.Domain)
+	if err != nil && offline {
+		return nil, fmt.Errorf("couldn't open the message store for %v: %w", config.Domain, err)
+	}
+
+	return &workspace{
+		config:  config,
+		client:  client,
+		events:  api.Websocket{Token: config.Token, Cookie: config.Cookie},
+		cache:   cache,
+		store:   store,
+		user:    cache.UserID,
+		loading: firstRun,
+	}, nil
+}
+
+func (ws *workspace) name() string {
+	if ws.config.Name != "" {
+		return ws.config.Name
+	}
+	return ws.config.Domain
+}
+
+func (a *app) runEvents(ctx context.Context, ws *workspace) {
+	events := make(chan tea.Msg)
+	go func() {
+		ws.events.Run(ctx, events)
+		close(events)
+	}()
+
+	for msg := range events {
+		a.MsgChan <- core.WorkspaceEventMsg{Workspace: ws.config.Domain, Msg: msg}
+	}
+}
+
+func (a *app) workspace(domain string) *workspace {
+	for _, ws := range a.workspaces {
+		if ws.config.Domain == domain {
+			return ws
+		}
+	}
+	return nil
+}
+
+func (a *app) isActive(ws *workspace) bool {
+	return ws == a.workspaces[a.activeWorkspace]
+}
+
+func (a *app) handleWorkspaceEvent(msg core.WorkspaceEventMsg) (tea.Model, tea.Cmd) {
+	ws := a.workspace(msg.Workspace)
+	if ws == nil {
+		return a, nil
+	}
+
+	if state, ok := msg.Msg.(core.ConnectionStateMsg); ok {
+		ws.connection = state.State
+		ws.retryAt = time.Time{}
+		if state.RetryIn > 0 {
+			ws.retryAt = time.Now().Add(state.RetryIn)
+		}
+	}
+
+	event, isEvent := msg.Msg.(core.HandleEventMsg)
+	if isEvent {
+		switch ev := event.Event.(type) {
+		case *api.MessageEvent:
+			a.notifyMessage(ws, ev)
+			a.trackThread(ws, ev)
+			a.trackMessageActivity(ws, ev)
+		case *api.ReactionAddedEvent:
+			a.trackReactionActivity(ws, ev)
+		case *api.ReactionRemovedEvent:
+			a.untrackReactionActivity(ws, ev)
+		}
+	}
+
+	if a.isActive(ws) {
+		return a.Update(msg.Msg)
+	}
+
+	if isEvent {
+		if ev, ok := event.Event.(*api.MessageEvent); ok {
+			a.trackBackgroundUnread(ws, ev)
+		}
+	}
+	return a, nil
+}
+
+func (a *app) trackBackgroundUnread(ws *workspace, ev *api.MessageEvent) {
+	if !countsAsUnread(ev) || ev.User == ws.user {
+		return
+	}
+
+	mentioned := strings.HasPrefix(ev.Channel, "D") || utils.MentionsUser(ev.Text, ws.user) || utils.MentionsEveryone(ev.Text)
+
+	ws.unread++
+	if mentioned {
+		ws.mentions++
+	}
+
+	if conv, ok := ws.cache.Conversations[ev.Channel]; ok {
+		if ev.Timestamp > conv.LatestMessage {
+			conv.LatestMessage = ev.Timestamp
+		}
+		conv.UnreadCount++
+		if mentioned {
+			conv.MentionCount++
+		}
+		a.saveCache(ws.cache)
+	}
+}
+
+func (a *app) switchWorkspace(index int, cmds *[]tea.Cmd) {
+	if index == a.activeWorkspace || index < 0 || index >= len(a.workspaces) {
+		return
+	}
+
+	current := a.workspaces[a.activeWorkspace]
+	current.cache = a.Cache
+	current.currentChannel = a.CurrentChannel
+	current.sidebar = a.sidebar
+
+	target := a.workspaces[index]
+	target.unread = 0
+	target.mentions = 0
+	a.activeWorkspace = index
+
+	a.Mutex.Lock()
+	a.Client = target.client
+	a.store = target.store
+	a.Events = target.events
+	a.Cache = target.cache
+	a.User = target.user
+	a.Mutex.Unlock()
+
+	a.CurrentChannel = target.currentChannel
+	a.connection = target.connection
+	a.retryAt = target.retryAt
+	if time.Until(a.retryAt) > 0 {
+		*cmds = append(*cmds, retryTick(a.retryAt))
+	}
+
+	a.images.clear()
+	a.popup.isVisible = false
+	a.popup.browser = browserState{}
+	a.completion.isVisible = false
+	a.chat.messages = []core.Message{}
+	a.chat.displayedMessages = nil
+	a.chat.selectedMessage = 0
+	a.chat.hasMore = false
+	a.chat.hasNewer = false
+	a.chat.loadingOlder = false
+	a.chat.loadingNewer = false
+	a.threadWindow.chat.messages = []core.Message{}
+	a.threadWindow.isOpen = false
+	a.threadWindow.parentTs = ""
+	a.threadWindow.focusTs = ""
+	if a.focused == FocusThreadChat || a.focused == FocusThreadInput {
+		a.focused = FocusInput
+	}
+
+	a.Config.ActiveWorkspace = target.config.Domain
+	go api.SaveConfig(a.Config)
+
+	a.InitialLoading = target.loading
+	a.loadingProgress = core.LoadingProgressMsg{}
+	if target.loaded {
+		a.sidebar = target.sidebar
+	} else {
+		target.loaded = true
+		a.sidebar = sidebar{}
+		a.LoadConversations(target)
+
+		if !a.Offline {
+			go a.loadCustomEmoji(target)
+		}
+	}
+
+	if !a.InitialLoading {
+		a.loadChannelHistory(cmds)
+	}
+
+	*cmds = append(*cmds, func() tea.Msg {
+		return tea.WindowSizeMsg{Width: a.width, Height: a.height}
+	})
+}
+
+func (a *app) workspaceLabel() string {
+	if len(a.workspaces) < 2 {
+		return ""
+	}
+	return utils.Sanitize(a.workspaces[a.activeWorkspace].name()) + " › "
+}
+
+func (a *app) backgroundUnread() string {
+	var parts []string
+	for _, ws := range a.workspaces {
+		if ws.unread == 0 {
+			continue
+		}
+
+		part := utils.Sanitize(ws.name()) + " ●"
+		if ws.mentions > 0 {
+			part += fmt.Sprintf(" @%d", ws.mentions)
+		}
+		parts = append(parts, part)
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return " · " + strings.Join(parts, " · ")
+}
@@ -41,13 +41,14 @@ var (
 type endMsg struct{}
 
 type model struct {
-	state    state
-	input    textinput.Model
-	list     list.Model
-	errorMsg string
-	cfg      core.Config
-	height   int
-	width    int
+	state     state
+	input     textinput.Model
+	list      list.Model
+	errorMsg  string
+	cfg       core.Config
+	workspace core.Workspace
+	height    int
+	width     int
 }
 
 type themeItem struct {
@@ -55,7 +56,7 @@ type themeItem struct {
 	Colors styles.Theme
 }
 
-func Start() model {
+func Start(domain string) model {
 	cfg, err := api.LoadConfig()
 	var theme styles.Theme
 	if err == nil && cfg.Theme != "" {
@@ -77,6 +78,16 @@ func Start() model {
 	i.TextStyle = lg.NewStyle().Foreground(theme.Text)
 	i.Cursor.Style = lg.NewStyle().Foreground(theme.Text)
 
+	workspace := core.Workspace{Domain: api.NormalizeDomain(domain)}
+	if workspace.Domain == "" {
+		workspace.Domain = api.DefaultDomain
+	}
+	if existing, err := api.FindWorkspace(cfg, workspace.Domain); err == nil {
+		i.SetValue(existing.Cookie)
+	} else if len(cfg.Workspaces) > 0 {
+		i.SetValue(cfg.Workspaces[0].Cookie)
+	}
+
 	var items []list.Item
 
 	items = []list.Item{
@@ -104,11 +115,13 @@ func Start() model {
 	}
 
 	return model{
-		state:  0,
-		input:  i,
-		list:   l,
-		height: 0,
-		width:  0,
+		state:     0,
+		input:     i,
+		list:      l,
+		cfg:       cfg,
+		workspace: workspace,
+		height:    0,
+		width:     0,
 	}
 }
 
@@ -134,16 +147,24 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "ctrl+c", "esc":
 				return m, tea.Quit
 			case "enter":
-				m.cfg.Cookie = strings.TrimSpace(m.input.Value())
+				m.workspace.Cookie = strings.TrimSpace(m.input.Value())
 
-				token, err := getToken(m.cfg.Cookie)
+				token, err := getToken(m.workspace.Domain, m.workspace.Cookie)
 				if err != nil {
-					m.errorMsg = "Invalid slack cookie"
-				} else {
-					m.cfg.Token = token
-					m.state = themeChange
-					m.errorMsg = ""
+					m.errorMsg = fmt.Sprintf("Invalid slack cookie for %v.slack.com", m.workspace.Domain)
+					return m, nil
 				}
+				m.workspace.Token = token
+				m.workspace.Name = workspaceName(m.workspace)
+				api.PutWorkspace(&m.cfg, m.workspace)
+				m.errorMsg = ""
+
+				if m.cfg.Theme != "" && len(m.cfg.Workspaces) > 1 {
+					api.SaveConfig(m.cfg)
+					m.state = end
+					return m, nil
+				}
+				m.state = themeChange
 				return m, nil
 			}
 			m.input, cmd = m.input.Update(msg)
@@ -183,13 +204,13 @@ func (m model) View() string {
 
 	switch m.state {
 	case initial:
-		s = headerStyle.Render("  Input your slack d cookie")
+		s = headerStyle.Render(fmt.Sprintf("  Input your slack d cookie for %v.slack.com", m.workspace.Domain))
 		s += "\n\n"
 		s += inputStyle.Render(m.input.View())
 	case themeChange:
 		s = m.list.View()
 	case end:
-		s = successStyle.Render("You can now use HackCLI!")
+		s = successStyle.Render(fmt.Sprintf("You can now use HackCLI with %v!", m.workspace.Name))
 		s += "\n\n"
 		s += successStyle.Render("To update your settings just edit the config file")
 	}
@@ -234,9 +255,9 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 	fmt.Fprint(w, fn(str))
 }
 
-func getToken(cookie string) (string, error) {
+func getToken(domain, cookie string) (string, error) {
 	client := http.Client{}
-	req, _ := http.NewRequest("GET", "https://hackclub.slack.com", nil)
+	req, _ := http.NewRequest("GET", fmt.Sprintf("https://%v.slack.com", domain), nil)
 	req.Header.Add("Cookie", fmt.Sprintf("d=%v", cookie))
 
 	resp, err := client.Do(req)
@@ -255,6 +276,14 @@ func getToken(cookie string) (string, error) {
 	return token, nil
 }
 
+func workspaceName(workspace core.Workspace) string {
+	auth, err := api.NewClient(workspace).AuthTest()
+	if err != nil || auth.Team == "" {
+		return workspace.Domain
+	}
+	return auth.Team
+}
+
 func initializeStyles(theme styles.Theme) {
 	successStyle = titleStyle.Foreground(styles.Green).Bold(true)
 	inputStyle = lg.NewStyle().MarginLeft(2).Foreground(theme.Primary)
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var shortcodeRegex = regexp.MustCompile(`:([a-z0-9_+'\-]+):(?::skin-tone-([2-6]):)?`)
+
+var skinTones = map[string]string{
+	"2": "\U0001F3FB",
+	"3": "\U0001F3FC",
+	"4": "\U0001F3FD",
+	"5": "\U0001F3FE",
+	"6": "\U0001F3FF",
+}
+
+var PopularEmoji = []string{
+	"+1", "heart", "joy", "eyes", "fire", "tada", "pray", "white_check_mark",
+	"100", "sob", "skull", "rocket", "raised_hands", "clap", "thinking_face", "sparkles",
+	"wave", "smile", "laughing", "upside_down_face", "heart_eyes", "sweat_smile", "partying_face", "star-struck",
+	"-1", "x", "warning", "rotating_light", "zap", "star", "bulb", "moyai",
+}
+
+func Emoji(name string, custom map[string]string) (string, bool) {
+	name = strings.Trim(name, ":")
+
+	base, tone, _ := strings.Cut(name, "::skin-tone-")
+
+	for range 3 {
+		target, ok := custom[base]
+		if !ok {
+			break
+		}
+		alias, isAlias := strings.CutPrefix(target, "alias:")
+		if !isAlias {
+			break
+		}
+		base = alias
+	}
+
+	emoji, ok := emojiTable[base]
+	if !ok {
+		return "", false
+	}
+
+	return emoji + skinTones[tone], true
+}
+
+func ReplaceShortcodes(text string, custom map[string]string) string {
+	return shortcodeRegex.ReplaceAllStringFunc(text, func(shortcode string) string {
+		match := shortcodeRegex.FindStringSubmatch(shortcode)
+
+		name := match[1]
+		if match[2] != "" {
+			name += "::skin-tone-" + match[2]
+		}
+
+		if emoji, ok := Emoji(name, custom); ok {
+			return emoji
+		}
+		return shortcode
+	})
+}
+
+func EmojiNames(custom map[string]string) []string {
+	names := make([]string, 0, len(emojiTable)+len(custom))
+
+	for name := range emojiTable {
+		names = append(names, name)
+	}
+	for name := range custom {
+		if _, exists := emojiTable[name]; !exists {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+var tokenRegex = regexp.MustCompile(`<([^<>]+)>`)
+
+var entityReplacer = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+func PlainMrkdwn(text string, userName, channelName func(id string) string, custom map[string]string) string {
+	text = tokenRegex.ReplaceAllStringFunc(text, func(token string) string {
+		value, label, _ := strings.Cut(token[1:len(token)-1], "|")
+
+		switch {
+		case strings.HasPrefix(value, "@"):
+			return "@" + userName(strings.TrimPrefix(value, "@"))
+		case strings.HasPrefix(value, "#"):
+			if label != "" {
+				return "#" + label
+			}
+			return "#" + channelName(strings.TrimPrefix(value, "#"))
+		case strings.HasPrefix(value, "!subteam^"):
+			if label != "" {
+				return label
+			}
+			return "@group"
+		case strings.HasPrefix(value, "!date^"):
+			return label
+		case strings.HasPrefix(value, "!"):
+			return "@" + strings.TrimPrefix(value, "!")
+		}
+
+		if label != "" && label != value {
+			return label + " (" + value + ")"
+		}
+		return value
+	})
+
+	return ReplaceShortcodes(DecodeEntities(text), custom)
+}
+
+func DecodeEntities(text string) string {
+	return entityReplacer.Replace(text)
+}
+
+func MentionsUser(text, userID string) bool {
+	return strings.Contains(text, "<@"+userID+">") || strings.Contains(text, "<@"+userID+"|")
+}
+
+func MentionsEveryone(text string) bool {
+	return strings.Contains(text, "<!here") || strings.Contains(text, "<!channel") || strings.Contains(text, "<!everyone")
+}
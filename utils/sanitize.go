@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	esc = 0x1b
+	bel = 0x07
+	csi = 0x9b
+	st  = 0x9c
+)
+
+func Sanitize(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+
+		switch {
+		case r == utf8.RuneError && size == 1:
+		case r == esc:
+			i += escapeLen(text[i:])
+		case r == csi:
+			i += csiLen(text[i:])
+		case isStringIntroducer(r):
+			i += controlStringLen(text[i:])
+		case r == '\n' || r == '\t':
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7f || (r >= 0x80 && r < 0xa0):
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+func escapeLen(rest string) int {
+	if rest == "" {
+		return 0
+	}
+
+	switch c := rest[0]; {
+	case c == '[':
+		return 1 + csiLen(rest[1:])
+	case c == ']' || c == 'P' || c == 'X' || c == '^' || c == '_':
+		return 1 + controlStringLen(rest[1:])
+	case c >= 0x20 && c <= 0x2f:
+		j := 1
+		for j < len(rest) && rest[j] >= 0x20 && rest[j] <= 0x2f {
+			j++
+		}
+		if j < len(rest) && rest[j] >= 0x30 && rest[j] <= 0x7e {
+			j++
+		}
+		return j
+	case c >= 0x30 && c <= 0x7e:
+		return 1
+	}
+	return 0
+}
+
+func csiLen(rest string) int {
+	for j := 0; j < len(rest); j++ {
+		c := rest[j]
+		if c >= 0x20 && c <= 0x3f {
+			continue
+		}
+		if c >= 0x40 && c <= 0x7e {
+			return j + 1
+		}
+		return j
+	}
+	return len(rest)
+}
+
+func controlStringLen(rest string) int {
+	for j := 0; j < len(rest); {
+		r, size := utf8.DecodeRuneInString(rest[j:])
+		switch {
+		case r == bel || r == st:
+			return j + size
+		case r == esc:
+			if j+1 < len(rest) && rest[j+1] == '\\' {
+				return j + 2
+			}
+			return j
+		}
+		j += size
+	}
+	return len(rest)
+}
+
+func isStringIntroducer(r rune) bool {
+	switch r {
+	case 0x90, 0x98, 0x9d, 0x9e, 0x9f:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func FuzzSanitize(f *testing.F) {
+	for _, seed := range []string{
+		"plain text",
+		"tab\tand\nnewline",
+		"\x1b[31mred\x1b[0m",
+		"\x1b]0;title\x07after",
+		"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\",
+		"\x1bPq#0;2;0;0;0\x1b\\",
+		"\x1b_Ga=T;AAAA\x1b\\",
+		"\u009b2J\u009d0;title\u009c\u0090dcs\u009c",
+		"\x1b",
+		"\x1b[",
+		"\xc2",
+		"emoji 🎉 and ümlauts",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, text string) {
+		got := Sanitize(text)
+
+		if !utf8.ValidString(got) {
+			t.Fatalf("Sanitize(%q) = %q, not valid UTF-8", text, got)
+		}
+		if strings.Contains(got, "\x1b") {
+			t.Fatalf("Sanitize(%q) = %q, contains ESC", text, got)
+		}
+		for _, r := range got {
+			if r >= 0x80 && r < 0xa0 {
+				t.Fatalf("Sanitize(%q) = %q, contains C1 control %U", text, got, r)
+			}
+			if (r < 0x20 && r != '\n' && r != '\t') || r == 0x7f {
+				t.Fatalf("Sanitize(%q) = %q, contains C0 control %U", text, got, r)
+			}
+		}
+	})
+}